@@ -0,0 +1,283 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError error of decoding one struct field
+type FieldError struct {
+	Field string // struct field name
+	Tag   string // "param" or "query"
+	Key   string // param or query name
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s %s (%s): %s", e.Tag, e.Key, e.Field, e.Err.Error())
+	}
+
+	return fmt.Sprintf("%s %s=%q (%s): %s", e.Tag, e.Key, e.Value, e.Field, e.Err.Error())
+}
+
+// BindError errors of all invalid fields
+type BindError []*FieldError
+
+func (e BindError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// ErrRequired error for missing required param or query
+var ErrRequired = errors.New("required")
+
+// Bind decode params and queries to struct v points to. Fields are bound by tags:
+//
+//	struct {
+//		ID   int      `param:"id"`
+//		Page int      `query:"page"`
+//		Tags []string `query:"tag"` // "tag=a&tag=b"
+//		Sort string   `query:"sort,required"`
+//	}
+//
+// Supported types are strings, ints, uints, floats, bools and slices of them.
+// Slices are decoded from all values of query, other fields from the first one.
+// Missing fields are left unchanged, unless they are required. Return BindError for invalid fields
+func Bind(v interface{}, params map[string]string, query url.Values) error {
+	dst := reflect.ValueOf(v)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() || dst.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("invalid struct pointer: %T", v)
+	}
+	dst = dst.Elem()
+
+	var errs BindError
+	for _, f := range bindFields(dst.Type()) {
+		values := []string{params[f.key]}
+		if f.tag == "query" {
+			values = query[f.key]
+		}
+
+		if len(values) == 0 || values[0] == "" {
+			if f.required {
+				errs = append(errs, f.error("", ErrRequired))
+			}
+			continue
+		}
+
+		if err := decodeValue(dst.Field(f.index), values); err != nil {
+			errs = append(errs, f.error(strings.Join(values, ","), err))
+		}
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Encode encode struct (or pointer to struct) to params and query by field tags, see Bind.
+// Queries with zero values are skipped, slices are encoded as repeated queries
+func Encode(v interface{}) (params map[string]string, query url.Values, err error) {
+	src := reflect.ValueOf(v)
+	if src.Kind() == reflect.Ptr && !src.IsNil() {
+		src = src.Elem()
+	}
+
+	if src.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("invalid struct: %T", v)
+	}
+
+	params, query = make(map[string]string), make(url.Values)
+	for _, f := range bindFields(src.Type()) {
+		field := src.Field(f.index)
+		if f.tag == "query" && isZero(field) {
+			continue
+		}
+
+		values, err := encodeValue(field)
+		if err != nil {
+			return nil, nil, f.error("", err)
+		}
+
+		if f.tag == "query" {
+			query[f.key] = values
+			continue
+		}
+
+		if len(values) != 1 {
+			return nil, nil, f.error("", errors.New("param can't be slice"))
+		}
+
+		params[f.key] = values[0]
+	}
+
+	return params, query, nil
+}
+
+type bindField struct {
+	index    int
+	name     string
+	tag      string
+	key      string
+	required bool
+}
+
+func (f bindField) error(value string, err error) *FieldError {
+	return &FieldError{Field: f.name, Tag: f.tag, Key: f.key, Value: value, Err: err}
+}
+
+func bindFields(typ reflect.Type) []bindField {
+	var fields []bindField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+
+		for _, tag := range []string{"param", "query"} {
+			value, ok := field.Tag.Lookup(tag)
+			if !ok || value == "-" {
+				continue
+			}
+
+			options := strings.Split(value, ",")
+			f := bindField{index: i, name: field.Name, tag: tag, key: options[0]}
+			if f.key == "" {
+				f.key = field.Name
+			}
+
+			for _, option := range options[1:] {
+				if option == "required" {
+					f.required = true
+				}
+			}
+
+			fields = append(fields, f)
+			break
+		}
+	}
+
+	return fields
+}
+
+func decodeValue(dst reflect.Value, values []string) error {
+	if dst.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(dst.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := decodeScalar(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+
+		dst.Set(slice)
+		return nil
+	}
+
+	return decodeScalar(dst, values[0])
+}
+
+func decodeScalar(dst reflect.Value, value string) error {
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("invalid bool")
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, dst.Type().Bits())
+		if err != nil {
+			return errors.New("invalid int")
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, dst.Type().Bits())
+		if err != nil {
+			return errors.New("invalid uint")
+		}
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, dst.Type().Bits())
+		if err != nil {
+			return errors.New("invalid float")
+		}
+		dst.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type: %s", dst.Type().String())
+	}
+
+	return nil
+}
+
+func encodeValue(src reflect.Value) ([]string, error) {
+	if src.Kind() == reflect.Slice {
+		values := make([]string, src.Len())
+		for i := range values {
+			value, err := encodeScalar(src.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = value
+		}
+
+		return values, nil
+	}
+
+	value, err := encodeScalar(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{value}, nil
+}
+
+func encodeScalar(src reflect.Value) (string, error) {
+	switch src.Kind() {
+	case reflect.String:
+		return src.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(src.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(src.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(src.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(src.Float(), 'f', -1, src.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("unsupported type: %s", src.Type().String())
+	}
+}
+
+// isZero return true for zero values of supported types
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice:
+		return v.Len() == 0
+	case reflect.String:
+		return v.String() == ""
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	default:
+		return false
+	}
+}
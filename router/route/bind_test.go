@@ -0,0 +1,112 @@
+package route
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type bindTestPage struct {
+	ID     int      `param:"id"`
+	Slug   string   `param:"slug"`
+	Page   uint     `query:"page"`
+	Draft  bool     `query:"draft"`
+	Score  float64  `query:"score"`
+	Tags   []string `query:"tag"`
+	Sort   string   `query:"sort,required"`
+	Ignore string   `query:"-"`
+	hidden string
+}
+
+func TestBind(t *testing.T) {
+	data := []struct {
+		name   string
+		params map[string]string
+		query  url.Values
+		result bindTestPage
+		errs   []string // invalid keys
+	}{
+		{
+			name:   "full",
+			params: map[string]string{"id": "42", "slug": "gas"},
+			query:  url.Values{"page": {"2"}, "draft": {"true"}, "score": {"1.5"}, "tag": {"a", "b"}, "sort": {"date"}, "Ignore": {"x"}},
+			result: bindTestPage{ID: 42, Slug: "gas", Page: 2, Draft: true, Score: 1.5, Tags: []string{"a", "b"}, Sort: "date"},
+		},
+		{
+			name:   "defaults",
+			params: map[string]string{"id": "1"},
+			query:  url.Values{"sort": {"name"}},
+			result: bindTestPage{ID: 1, Page: 1, Sort: "name"},
+		},
+		{
+			name:   "invalid",
+			params: map[string]string{"id": "gas"},
+			query:  url.Values{"page": {"-1"}, "draft": {"maybe"}},
+			result: bindTestPage{Page: 1},
+			errs:   []string{"id", "page", "draft", "sort"},
+		},
+	}
+
+	for _, el := range data {
+		result := bindTestPage{Page: 1}
+		err := Bind(&result, el.params, el.query)
+
+		var keys []string
+		if err != nil {
+			bindErr, ok := err.(BindError)
+			if !ok {
+				t.Errorf("%s: unexpected error: %s", el.name, err.Error())
+				continue
+			}
+
+			for _, fieldErr := range bindErr {
+				keys = append(keys, fieldErr.Key)
+			}
+		}
+
+		if !reflect.DeepEqual(keys, el.errs) {
+			t.Errorf("%s: want errors for: %v, got: %v", el.name, el.errs, err)
+		}
+
+		if !reflect.DeepEqual(result, el.result) {
+			t.Errorf("%s: want: %+v, got: %+v", el.name, el.result, result)
+		}
+	}
+
+	if err := Bind(bindTestPage{}, nil, nil); err == nil {
+		t.Error("expected error for not pointer")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	params, query, err := Encode(bindTestPage{ID: 42, Slug: "gas", Tags: []string{"a", "b"}, Sort: "date", Ignore: "x"})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(params, map[string]string{"id": "42", "slug": "gas"}) {
+		t.Errorf("invalid params: %v", params)
+	}
+
+	if !reflect.DeepEqual(query, url.Values{"tag": {"a", "b"}, "sort": {"date"}}) {
+		t.Errorf("invalid query: %v", query)
+	}
+
+	table, err := New([]Route{{Name: "page", Path: "/pages/:id(\\d+)/:slug"}})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	path, err := table.PathStruct("page", &bindTestPage{ID: 1, Slug: "gas", Page: 2})
+	if err != nil || path != "/pages/1/gas?page=2" {
+		t.Errorf("invalid path: %s, %v", path, err)
+	}
+
+	if _, _, err := Encode(struct {
+		M map[string]string `query:"m"`
+	}{M: map[string]string{}}); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+}
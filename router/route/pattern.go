@@ -0,0 +1,351 @@
+package route
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	segmentStatic = iota // "users"
+	segmentParam         // ":id", ":id?"
+	segmentMixed         // ":id(\d+)", "file-:name.:ext"
+	segmentSplat         // "*rest"
+)
+
+// pattern compiled route path
+type pattern struct {
+	path     string
+	segments []*segment
+
+	leading, trailing bool // path starts or ends with "/"
+}
+
+type segment struct {
+	kind  int
+	parts []part
+
+	re     *regexp.Regexp // segmentMixed regexp
+	groups []int          // indexes of params groups in re
+}
+
+type part struct {
+	static string // static text, if name is empty
+
+	name       string
+	optional   bool
+	constraint *regexp.Regexp // anchored param constraint
+}
+
+// optional return true if segment is single optional param, so it can be skipped
+func (seg *segment) optional() bool {
+	return len(seg.parts) == 1 && seg.parts[0].name != "" && seg.parts[0].optional
+}
+
+func (seg *segment) names() []string {
+	var names []string
+	for _, p := range seg.parts {
+		if p.name != "" {
+			names = append(names, p.name)
+		}
+	}
+
+	return names
+}
+
+// parsePattern compile route path. Segments syntax:
+//
+// "users" - static text
+// ":id" - param
+// ":id?" - optional param
+// ":id(\d+)" - param matching regexp
+// "file-:name.:ext" - params with static text
+// "*rest" - rest of path, only as last segment
+func parsePattern(path string) (*pattern, error) {
+	p := &pattern{
+		path:     path,
+		leading:  strings.HasPrefix(path, "/"),
+		trailing: len(path) > 1 && strings.HasSuffix(path, "/"),
+	}
+
+	rawSegments, err := splitPattern(path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for i, raw := range rawSegments {
+		seg, err := parseSegment(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %s: %s", path, err.Error())
+		}
+
+		if seg.kind == segmentSplat && i != len(rawSegments)-1 {
+			return nil, fmt.Errorf("invalid path %s: splat must be the last segment", path)
+		}
+
+		for _, name := range seg.names() {
+			if names[name] {
+				return nil, fmt.Errorf("invalid path %s: duplicate param %s", path, name)
+			}
+			names[name] = true
+		}
+
+		p.segments = append(p.segments, seg)
+	}
+
+	return p, nil
+}
+
+// splitPattern split path by "/" outside of constraints. Empty segments are skipped
+func splitPattern(path string) ([]string, error) {
+	var segments []string
+
+	depth, start := 0, 0
+	for i := 0; i <= len(path); i++ {
+		if i < len(path) {
+			switch path[i] {
+			case '\\':
+				i++
+				continue
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				if depth < 0 {
+					return nil, fmt.Errorf("invalid path %s: unexpected \")\"", path)
+				}
+				continue
+			case '/':
+				if depth != 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		if i > start {
+			segments = append(segments, path[start:i])
+		}
+		start = i + 1
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid path %s: unclosed \"(\"", path)
+	}
+
+	return segments, nil
+}
+
+func parseSegment(raw string) (*segment, error) {
+	if strings.HasPrefix(raw, "*") {
+		name := raw[1:]
+		if !isName(name) {
+			return nil, fmt.Errorf("invalid splat name %q", name)
+		}
+
+		return &segment{kind: segmentSplat, parts: []part{{name: name}}}, nil
+	}
+
+	var parts []part
+	var static strings.Builder
+
+	for i := 0; i < len(raw); {
+		if raw[i] != ':' {
+			static.WriteByte(raw[i])
+			i++
+			continue
+		}
+
+		if static.Len() != 0 {
+			parts = append(parts, part{static: static.String()})
+			static.Reset()
+		}
+
+		end := i + 1
+		for end < len(raw) && isNameChar(raw[end]) {
+			end++
+		}
+
+		p := part{name: raw[i+1 : end]}
+		if p.name == "" {
+			return nil, fmt.Errorf("empty param name in %q", raw)
+		}
+
+		if end < len(raw) && raw[end] == '(' {
+			closing := closingParen(raw, end)
+			if closing == -1 {
+				return nil, fmt.Errorf("unclosed constraint of %s", p.name)
+			}
+
+			re, err := regexp.Compile("^(?:" + raw[end+1:closing] + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint of %s: %s", p.name, err.Error())
+			}
+
+			p.constraint = re
+			end = closing + 1
+		}
+
+		if end < len(raw) && raw[end] == '?' {
+			p.optional = true
+			end++
+		}
+
+		parts = append(parts, p)
+		i = end
+	}
+
+	if static.Len() != 0 {
+		parts = append(parts, part{static: static.String()})
+	}
+
+	seg := &segment{parts: parts}
+	switch {
+	case len(parts) == 1 && parts[0].name == "":
+		seg.kind = segmentStatic
+	case len(parts) == 1 && parts[0].constraint == nil:
+		seg.kind = segmentParam
+	default:
+		seg.kind = segmentMixed
+		if err := seg.compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return seg, nil
+}
+
+// compile build regexp matching mixed segment
+func (seg *segment) compile() error {
+	var b strings.Builder
+	b.WriteString("^")
+
+	for i, p := range seg.parts {
+		if p.name == "" {
+			b.WriteString(regexp.QuoteMeta(p.static))
+			continue
+		}
+
+		expr := ".+?"
+		if p.constraint != nil {
+			expr = strings.TrimSuffix(strings.TrimPrefix(p.constraint.String(), "^"), "$")
+		}
+
+		fmt.Fprintf(&b, "(?P<__p%d>%s)", i, expr)
+		if p.optional {
+			b.WriteString("?")
+		}
+	}
+
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return err
+	}
+
+	seg.re = re
+	for i, name := range re.SubexpNames() {
+		if strings.HasPrefix(name, "__p") {
+			seg.groups = append(seg.groups, i)
+		}
+	}
+
+	return nil
+}
+
+// fill build path with escaped params. Return error if required param is missing or param doesn't match its constraint
+func (p *pattern) fill(params map[string]string) (string, error) {
+	var segments []string
+	for _, seg := range p.segments {
+		switch seg.kind {
+		case segmentStatic:
+			segments = append(segments, seg.parts[0].static)
+		case segmentSplat:
+			if rest := strings.Trim(params[seg.parts[0].name], "/"); rest != "" {
+				for _, part := range strings.Split(rest, "/") {
+					segments = append(segments, url.PathEscape(part))
+				}
+			}
+		default:
+			var b strings.Builder
+			for _, part := range seg.parts {
+				if part.name == "" {
+					b.WriteString(part.static)
+					continue
+				}
+
+				value := params[part.name]
+				if value == "" {
+					if part.optional {
+						continue
+					}
+
+					return "", fmt.Errorf("missing param %s for path %s", part.name, p.path)
+				}
+
+				if part.constraint != nil && !part.constraint.MatchString(value) {
+					return "", fmt.Errorf("param %s=%q doesn't match %s in path %s", part.name, value, part.constraint.String(), p.path)
+				}
+
+				b.WriteString(url.PathEscape(value))
+			}
+
+			if b.Len() != 0 {
+				segments = append(segments, b.String())
+			}
+		}
+	}
+
+	path := strings.Join(segments, "/")
+	if p.leading {
+		path = "/" + path
+	}
+
+	if p.trailing && len(segments) != 0 {
+		path += "/"
+	}
+
+	return path, nil
+}
+
+func closingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isNameChar(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
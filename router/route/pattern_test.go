@@ -0,0 +1,132 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPatternMatch(t *testing.T) {
+	routes := []Route{
+		{Name: "user", Path: "/users/:id(\\d+)", Exact: true},
+		{Name: "userName", Path: "/users/:name", Exact: true},
+		{Name: "userTab", Path: "/users/:id(\\d+)/:tab?", Exact: true},
+		{Name: "file", Path: "/files/:name.:ext", Exact: true},
+		{Name: "fileVersion", Path: "/files/:name-v:version(\\d+).:ext", Exact: true},
+		{Name: "docs", Path: "/docs/*page", Exact: true},
+		{Name: "page", Path: "/:lang(en|ru)?/about", Exact: true},
+	}
+
+	table, err := New(routes)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	data := []struct {
+		path   string
+		name   string
+		params map[string]string
+	}{
+		{path: "/users/42", name: "user", params: map[string]string{"id": "42"}},
+		{path: "/users/gas", name: "userName", params: map[string]string{"name": "gas"}},
+		{path: "/users/42/posts", name: "userTab", params: map[string]string{"id": "42", "tab": "posts"}},
+		{path: "/users/gas/posts"},
+		{path: "/files/router.tar.gz", name: "file", params: map[string]string{"name": "router", "ext": "tar.gz"}},
+		{path: "/files/router-v2.go", name: "fileVersion", params: map[string]string{"name": "router", "version": "2", "ext": "go"}},
+		{path: "/files/router"},
+		{path: "/docs", name: "docs", params: map[string]string{"page": ""}},
+		{path: "/docs/router/install", name: "docs", params: map[string]string{"page": "router/install"}},
+		{path: "/about", name: "page", params: map[string]string{}},
+		{path: "/ru/about", name: "page", params: map[string]string{"lang": "ru"}},
+		{path: "/de/about"},
+	}
+
+	for _, el := range data {
+		match, ok := table.Match(el.path)
+		if el.name == "" {
+			if ok {
+				t.Errorf("%s: unexpected match: %s %v", el.path, match.Route.Name, match.Params)
+			}
+			continue
+		}
+
+		if !ok {
+			t.Errorf("%s: route not found", el.path)
+			continue
+		}
+
+		if match.Route.Name != el.name || !reflect.DeepEqual(match.Params, el.params) {
+			t.Errorf("%s: want: %s %v, got: %s %v", el.path, el.name, el.params, match.Route.Name, match.Params)
+		}
+	}
+}
+
+func TestFillPath(t *testing.T) {
+	data := []struct {
+		path   string
+		params map[string]string
+		result string
+		err    bool
+	}{
+		{path: "/users/:id", params: map[string]string{"id": "1"}, result: "/users/1"},
+		{path: "/users/:id", err: true},
+		{path: "/users/:id/", params: map[string]string{"id": "1"}, result: "/users/1/"},
+		{path: "/users/:id?", result: "/users"},
+		{path: "/users/:id?/:tab?", params: map[string]string{"id": "1"}, result: "/users/1"},
+		{path: "/users/:id(\\d+)", params: map[string]string{"id": "1"}, result: "/users/1"},
+		{path: "/users/:id(\\d+)", params: map[string]string{"id": "gas"}, err: true},
+		{path: "/files/:name.:ext", params: map[string]string{"name": "a", "ext": "go"}, result: "/files/a.go"},
+		{path: "/files/:name.:ext", params: map[string]string{"name": "a"}, err: true},
+		{path: "/files/:name.:ext?", params: map[string]string{"name": "a"}, result: "/files/a."},
+		{path: "/docs/*page", params: map[string]string{"page": "router/install"}, result: "/docs/router/install"},
+		{path: "/docs/*page", result: "/docs"},
+		{path: "/", result: "/"},
+	}
+
+	for _, el := range data {
+		result, err := FillPath(el.path, el.params)
+		if el.err {
+			if err == nil {
+				t.Errorf("%s %v: expected error, got: %s", el.path, el.params, result)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s %v: unexpected error: %s", el.path, el.params, err.Error())
+			continue
+		}
+
+		if result != el.result {
+			t.Errorf("%s %v: want: %s, got: %s", el.path, el.params, el.result, result)
+		}
+	}
+}
+
+func TestInvalidPattern(t *testing.T) {
+	paths := []string{
+		"/users/:",
+		"/users/:id(\\d+",
+		"/users/:id)",
+		"/users/:id([)",
+		"/users/:id/:id",
+		"/docs/*page/edit",
+		"/docs/*",
+	}
+
+	for _, path := range paths {
+		if _, err := parsePattern(path); err == nil {
+			t.Errorf("%s: expected error", path)
+		}
+	}
+
+	table, err := New([]Route{{Name: "valid", Path: "/"}, {Name: "invalid", Path: paths[1]}})
+	if err == nil || table == nil {
+		t.Error("expected table with error")
+		return
+	}
+
+	if _, err := table.Path("invalid", nil, nil); err == nil {
+		t.Error("expected error for invalid route")
+	}
+}
@@ -0,0 +1,111 @@
+package route
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SplitURL split url to path, query and fragment: "/a?b=c#d" => "/a", "b=c", "d"
+func SplitURL(rawURL string) (path, query, fragment string) {
+	path = rawURL
+
+	if i := strings.Index(path, "#"); i != -1 {
+		path, fragment = path[:i], path[i+1:]
+	}
+
+	if i := strings.Index(path, "?"); i != -1 {
+		path, query = path[:i], path[i+1:]
+	}
+
+	return path, query, fragment
+}
+
+// ParseQuery parse and unescape query string: "foo=bar&tag=a&tag=b" => {"foo": ["bar"], "tag": ["a", "b"]}.
+// Parameter without "=" has empty value. Parameters with invalid escaping are skipped,
+// error for the first of them is returned with other parameters
+func ParseQuery(query string) (url.Values, error) {
+	values := make(url.Values)
+
+	var err error
+	for _, param := range strings.Split(query, "&") {
+		if len(param) == 0 {
+			continue
+		}
+
+		key, value := param, ""
+		if i := strings.Index(param, "="); i != -1 {
+			key, value = param[:i], param[i+1:]
+		}
+
+		key, keyErr := url.QueryUnescape(key)
+		value, valueErr := url.QueryUnescape(value)
+		if keyErr != nil || valueErr != nil {
+			if err == nil {
+				err = fmt.Errorf("invalid query parametr: %s", param)
+			}
+			continue
+		}
+
+		values[key] = append(values[key], value)
+	}
+
+	return values, err
+}
+
+// EncodeQuery build escaped query string sorted by keys: {"foo": ["bar"]} => "?foo=bar".
+// Return empty string for empty query
+func EncodeQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	return "?" + query.Encode()
+}
+
+// Values convert single valued queries to url.Values
+func Values(queries map[string]string) url.Values {
+	values := make(url.Values, len(queries))
+	for key, value := range queries {
+		values.Set(key, value)
+	}
+
+	return values
+}
+
+// First return first value of each query
+func First(query url.Values) map[string]string {
+	queries := make(map[string]string, len(query))
+	for key := range query {
+		queries[key] = query.Get(key)
+	}
+
+	return queries
+}
+
+// ParseURL split url and unescape its query and fragment. Path is returned as is, Table.Match unescapes it by segments
+func ParseURL(rawURL string) (path string, query url.Values, fragment string, err error) {
+	path, rawQuery, rawFragment := SplitURL(rawURL)
+
+	query, err = ParseQuery(rawQuery)
+
+	fragment, fragmentErr := url.PathUnescape(rawFragment)
+	if fragmentErr != nil {
+		fragment = rawFragment
+		if err == nil {
+			err = fmt.Errorf("invalid fragment: %s", rawFragment)
+		}
+	}
+
+	return path, query, fragment, err
+}
+
+// JoinURL join path, escaped query and fragment: "/a", {"b": ["c"]}, "d" => "/a?b=c#d"
+func JoinURL(path string, query url.Values, fragment string) string {
+	path += EncodeQuery(query)
+	if fragment != "" {
+		path += (&url.URL{Fragment: fragment}).String()
+	}
+
+	return path
+}
@@ -0,0 +1,74 @@
+package route
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestSplitURL(t *testing.T) {
+	data := []struct {
+		url, path, query, fragment string
+	}{
+		{url: "/a", path: "/a"},
+		{url: "/a?b=c", path: "/a", query: "b=c"},
+		{url: "/a#d", path: "/a", fragment: "d"},
+		{url: "/a?b=c#d?e", path: "/a", query: "b=c", fragment: "d?e"},
+		{url: "some.com/wow?foo=bar", path: "some.com/wow", query: "foo=bar"},
+	}
+
+	for _, el := range data {
+		path, query, fragment := SplitURL(el.url)
+		if path != el.path || query != el.query || fragment != el.fragment {
+			t.Errorf("%s: want: %q %q %q, got: %q %q %q", el.url, el.path, el.query, el.fragment, path, query, fragment)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	data := []struct {
+		query  string
+		values url.Values
+		err    bool
+	}{
+		{query: "", values: url.Values{}},
+		{query: "foo=bar&some=wow", values: url.Values{"foo": {"bar"}, "some": {"wow"}}},
+		{query: "foo=bar&&", values: url.Values{"foo": {"bar"}}},
+		{query: "tag=a&tag=b&tag=", values: url.Values{"tag": {"a", "b", ""}}},
+		{query: "flag&expr=a=b", values: url.Values{"flag": {""}, "expr": {"a=b"}}},
+		{query: "q=gas+router&path=%2Fa%3Fb%26c", values: url.Values{"q": {"gas router"}, "path": {"/a?b&c"}}},
+		{query: "bad=%zz&some=wow", values: url.Values{"some": {"wow"}}, err: true},
+	}
+
+	for _, el := range data {
+		values, err := ParseQuery(el.query)
+		if (err != nil) != el.err {
+			t.Errorf("%s: unexpected error: %v", el.query, err)
+		}
+
+		if !reflect.DeepEqual(values, el.values) {
+			t.Errorf("%s: want: %v, got: %v", el.query, el.values, values)
+		}
+	}
+}
+
+func TestJoinURL(t *testing.T) {
+	data := []struct {
+		path     string
+		query    url.Values
+		fragment string
+		url      string
+	}{
+		{path: "/a", url: "/a"},
+		{path: "/a", query: url.Values{}, url: "/a"},
+		{path: "/a", query: url.Values{"b": {"2"}, "a": {"1"}}, url: "/a?a=1&b=2"},
+		{path: "/a", query: url.Values{"tag": {"x", "y"}}, fragment: "top", url: "/a?tag=x&tag=y#top"},
+		{path: "/a", query: url.Values{"q": {"a b&c=d"}}, fragment: "a b", url: "/a?q=a+b%26c%3Dd#a%20b"},
+	}
+
+	for _, el := range data {
+		if result := JoinURL(el.path, el.query, el.fragment); result != el.url {
+			t.Errorf("want: %s, got: %s", el.url, result)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package route
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	table, err := New([]Route{
+		{Name: "user", Path: "/users/:id", Exact: true},
+		{Name: "file", Path: "/files/:name.:ext", Exact: true},
+		{Name: "docs", Path: "/docs/*page", Exact: true},
+		{Name: "search", Path: "/search", Exact: true},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	data := []struct {
+		name     string
+		params   map[string]string
+		query    url.Values
+		fragment string
+	}{
+		{name: "user", params: map[string]string{"id": "42"}},
+		{name: "user", params: map[string]string{"id": "a b"}},
+		{name: "user", params: map[string]string{"id": "a/b"}},
+		{name: "user", params: map[string]string{"id": "a?b#c"}},
+		{name: "user", params: map[string]string{"id": "100%"}},
+		{name: "user", params: map[string]string{"id": "a+b=c&d"}},
+		{name: "user", params: map[string]string{"id": "über"}},
+		{name: "file", params: map[string]string{"name": "my file", "ext": "tar%gz"}},
+		{name: "docs", params: map[string]string{"page": "guide/first steps"}},
+		{name: "docs", params: map[string]string{"page": ""}},
+		{name: "search", query: url.Values{"q": {"gas router"}}},
+		{name: "search", query: url.Values{"q": {"a=b&c=d"}, "page": {"2"}}},
+		{name: "search", query: url.Values{"tag": {"x", "y", "x"}}},
+		{name: "search", query: url.Values{"q": {"?#&=+%"}}, fragment: "results"},
+		{name: "search", query: url.Values{"empty": {""}}, fragment: "a b#c/d?e"},
+		{name: "search", query: url.Values{"ключ": {"значение"}}, fragment: "über"},
+	}
+
+	for _, el := range data {
+		rawURL, err := table.URL(el.name, el.params, el.query, el.fragment)
+		if err != nil {
+			t.Errorf("%s %v: unexpected error: %s", el.name, el.params, err.Error())
+			continue
+		}
+
+		path, query, fragment, err := ParseURL(rawURL)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", rawURL, err.Error())
+			continue
+		}
+
+		match, ok := table.Match(path)
+		if !ok {
+			t.Errorf("%s: route not found", rawURL)
+			continue
+		}
+
+		params := el.params
+		if params == nil {
+			params = map[string]string{}
+		}
+
+		wantQuery := el.query
+		if wantQuery == nil {
+			wantQuery = url.Values{}
+		}
+
+		if match.Route.Name != el.name || !reflect.DeepEqual(match.Params, params) {
+			t.Errorf("%s: want: %s %v, got: %s %v", rawURL, el.name, params, match.Route.Name, match.Params)
+		}
+
+		if !reflect.DeepEqual(query, wantQuery) || fragment != el.fragment {
+			t.Errorf("%s: want: %v %q, got: %v %q", rawURL, wantQuery, el.fragment, query, fragment)
+		}
+
+		again, err := table.URL(match.Route.Name, match.Params, query, fragment)
+		if err != nil || again != rawURL {
+			t.Errorf("%s: url wasn't stable: %s, %v", rawURL, again, err)
+		}
+	}
+}
+
+func TestRoundTripStruct(t *testing.T) {
+	type page struct {
+		ID    string   `param:"id"`
+		Query string   `query:"q"`
+		Page  int      `query:"page"`
+		Tags  []string `query:"tag"`
+	}
+
+	table, err := New([]Route{{Name: "user", Path: "/users/:id", Exact: true}})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	src := page{ID: "a/b c", Query: "x=y&z", Page: 3, Tags: []string{"a,b", "c d"}}
+
+	rawURL, err := table.PathStruct("user", src)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	path, query, _, err := ParseURL(rawURL)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	match, ok := table.Match(path)
+	if !ok {
+		t.Errorf("%s: route not found", rawURL)
+		return
+	}
+
+	var dst page
+	if err := Bind(&dst, match.Params, query); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(src, dst) {
+		t.Errorf("%s: want: %+v, got: %+v", rawURL, src, dst)
+	}
+}
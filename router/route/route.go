@@ -0,0 +1,144 @@
+// Package route provides routes matching and urls building without DOM, so the same routes can be used in browser and on server
+package route
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Route route pattern
+type Route struct {
+	Name string
+
+	// Path route path. Segments can be static ("users"), params (":id"), optional params (":id?"),
+	// params with constraint (":id(\d+)"), several params with static text ("file-:name.:ext")
+	// and splat matching rest of path ("*rest"), which must be the last segment
+	Path string
+
+	Exact bool // if false route matches all paths starting with Path
+}
+
+// Match matched route
+type Match struct {
+	Index  int // index of route in table
+	Route  Route
+	Params map[string]string // /links/:foo => {"foo": "bar"}
+}
+
+// Table compiled routes table
+type Table struct {
+	routes   []Route
+	patterns []*pattern
+	errors   []error
+	names    map[string]int
+	tree     *tree
+}
+
+// New compile routes table. Routes with invalid paths are never matched,
+// error for the first of them is returned with table
+func New(routes []Route) (*Table, error) {
+	t := &Table{
+		routes:   routes,
+		patterns: make([]*pattern, len(routes)),
+		errors:   make([]error, len(routes)),
+		names:    make(map[string]int),
+		tree:     newTree(),
+	}
+
+	var firstErr error
+	for i, route := range routes {
+		if _, ok := t.names[route.Name]; !ok && route.Name != "" {
+			t.names[route.Name] = i
+		}
+
+		p, err := parsePattern(route.Path)
+		if err != nil {
+			t.errors[i] = err
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		t.patterns[i] = p
+		t.tree.add(i, p, route.Exact)
+	}
+
+	return t, firstErr
+}
+
+// Routes return table routes
+func (t *Table) Routes() []Route {
+	return t.routes
+}
+
+// Route return route by name
+func (t *Table) Route(name string) (Route, bool) {
+	i, ok := t.names[name]
+	if !ok {
+		return Route{}, false
+	}
+
+	return t.routes[i], true
+}
+
+// Match find route for path. Query and fragment are ignored
+func (t *Table) Match(path string) (*Match, bool) {
+	index, params, ok := t.tree.match(path)
+	if !ok {
+		return nil, false
+	}
+
+	return &Match{
+		Index:  index,
+		Route:  t.routes[index],
+		Params: params,
+	}, true
+}
+
+// Path build url for route with params and queries.
+// Return error if route is undefined or required param is missing
+func (t *Table) Path(name string, params, queries map[string]string) (string, error) {
+	return t.URL(name, params, Values(queries), "")
+}
+
+// URL build url for route with escaped params, query and fragment.
+// Return error if route is undefined or required param is missing
+func (t *Table) URL(name string, params map[string]string, query url.Values, fragment string) (string, error) {
+	i, ok := t.names[name]
+	if !ok {
+		return "", fmt.Errorf("undefined route: %s", name)
+	}
+
+	if t.errors[i] != nil {
+		return "", t.errors[i]
+	}
+
+	path, err := t.patterns[i].fill(params)
+	if err != nil {
+		return "", err
+	}
+
+	return JoinURL(path, query, fragment), nil
+}
+
+// PathStruct build url for route with params and query encoded from struct by Encode
+func (t *Table) PathStruct(name string, v interface{}) (string, error) {
+	params, query, err := Encode(v)
+	if err != nil {
+		return "", err
+	}
+
+	return t.URL(name, params, query, "")
+}
+
+// FillPath replace params in path by their escaped values: "/users/:id", {"id": "1"} => "/users/1".
+// Return error if required param is missing
+func FillPath(path string, params map[string]string) (string, error) {
+	p, err := parsePattern(path)
+	if err != nil {
+		return "", err
+	}
+
+	return p.fill(params)
+}
@@ -0,0 +1,119 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testRoutes = []Route{
+	{Name: "home", Path: "/", Exact: true},
+	{Name: "user", Path: "/users/:id", Exact: true},
+	{Name: "userPost", Path: "/users/:id/posts/:post", Exact: true},
+	{Name: "file", Path: "/files/file-:name", Exact: true},
+	{Name: "docs", Path: "/docs"},
+}
+
+func TestTableMatch(t *testing.T) {
+	table, err := New(testRoutes)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	data := []struct {
+		path   string
+		name   string
+		params map[string]string
+	}{
+		{path: "/", name: "home", params: map[string]string{}},
+		{path: "/users/1?tab=posts#top", name: "user", params: map[string]string{"id": "1"}},
+		{path: "/users/1/posts/2", name: "userPost", params: map[string]string{"id": "1", "post": "2"}},
+		{path: "/docs/router/install", name: "docs", params: map[string]string{}},
+		{path: "/users", name: ""},
+		{path: "/users/1/comments", name: ""},
+	}
+
+	for _, el := range data {
+		match, ok := table.Match(el.path)
+		if el.name == "" {
+			if ok {
+				t.Errorf("%s: unexpected match: %s", el.path, match.Route.Name)
+			}
+			continue
+		}
+
+		if !ok {
+			t.Errorf("%s: route not found", el.path)
+			continue
+		}
+
+		if match.Route.Name != el.name || testRoutes[match.Index].Name != el.name || !reflect.DeepEqual(match.Params, el.params) {
+			t.Errorf("%s: want: %s %v, got: %s %v", el.path, el.name, el.params, match.Route.Name, match.Params)
+		}
+	}
+}
+
+func TestTablePath(t *testing.T) {
+	table, err := New(testRoutes)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	data := []struct {
+		name    string
+		params  map[string]string
+		queries map[string]string
+		path    string
+		err     bool
+	}{
+		{name: "home", path: "/"},
+		{name: "user", params: map[string]string{"id": "1"}, path: "/users/1"},
+		{name: "userPost", params: map[string]string{"id": "1", "post": "2"}, queries: map[string]string{"b": "2", "a": "1"}, path: "/users/1/posts/2?a=1&b=2"},
+		{name: "file", params: map[string]string{"name": "gas"}, path: "/files/file-gas"},
+		{name: "user", params: map[string]string{"id": ":id"}, path: "/users/:id"},
+		{name: "user", err: true},
+		{name: "docs", queries: map[string]string{}, path: "/docs"},
+		{name: "unknown", err: true},
+	}
+
+	for _, el := range data {
+		path, err := table.Path(el.name, el.params, el.queries)
+		if el.err {
+			if err == nil {
+				t.Errorf("%s: expected error", el.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", el.name, err.Error())
+			continue
+		}
+
+		if path != el.path {
+			t.Errorf("%s: want: %s, got: %s", el.name, el.path, path)
+		}
+	}
+}
+
+func TestTableRoute(t *testing.T) {
+	table, err := New(append(testRoutes, Route{Name: "home", Path: "/home"}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	route, ok := table.Route("home")
+	if !ok || route.Path != "/" {
+		t.Errorf("invalid route: %v", route)
+	}
+
+	if _, ok := table.Route("unknown"); ok {
+		t.Error("found undefined route")
+	}
+
+	if len(table.Routes()) != len(testRoutes)+1 {
+		t.Errorf("invalid routes: %v", table.Routes())
+	}
+}
@@ -0,0 +1,43 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServerMatch(t *testing.T) {
+	table, err := New(testRoutes)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	data := []struct {
+		url string
+		id  string
+	}{
+		{url: "/users/1", id: "1"},
+		{url: "/users/a%2Fb", id: "a/b"},
+		{url: "/users/a%252Fb", id: "a%2Fb"},
+		{url: "/users/a%20b?tab=posts", id: "a b"},
+	}
+
+	for _, el := range data {
+		var id string
+		var ok bool
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var match *Match
+			match, ok = table.Match(r.URL.EscapedPath())
+			if ok {
+				id = match.Params["id"]
+			}
+		})
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", el.url, nil))
+
+		if !ok || id != el.id {
+			t.Errorf("%s: invalid id want: %q, got: %q (matched: %t)", el.url, el.id, id, ok)
+		}
+	}
+}
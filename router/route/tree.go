@@ -0,0 +1,208 @@
+package route
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// tree routes tree matching path by segments.
+// Segments are ranked: static, with static text ("file-:name"), constrained (":id(\d+)"), params (":id"),
+// splats and not exact routes, so routes declaration order matters only for routes with the same path
+type tree struct {
+	root *treeNode
+}
+
+type treeNode struct {
+	static map[string]*treeNode
+	edges  []*treeEdge // mixed segments
+	param  *treeNode
+
+	exact  *treeLeaf // route ending at this node
+	prefix *treeLeaf // not exact route or splat matching rest of path
+}
+
+// treeEdge mixed segment edge
+type treeEdge struct {
+	seg  *segment
+	node *treeNode
+}
+
+type treeLeaf struct {
+	index  int      // index in Table routes
+	params []string // params names in order of appearance
+	splat  string   // splat param name
+}
+
+func newTree() *tree {
+	return &tree{root: &treeNode{}}
+}
+
+// add add route pattern to tree. If there is route with the same path, the first one is used
+func (t *tree) add(index int, p *pattern, exact bool) {
+	t.insert(t.root, &treeLeaf{index: index}, p.segments, exact)
+}
+
+// insert insert segments to tree. Optional segments are inserted both with and without them
+func (t *tree) insert(n *treeNode, leaf *treeLeaf, segments []*segment, exact bool) {
+	if len(segments) == 0 {
+		if exact {
+			if n.exact == nil {
+				n.exact = leaf
+			}
+		} else if n.prefix == nil {
+			n.prefix = leaf
+		}
+
+		return
+	}
+
+	seg := segments[0]
+	if seg.optional() {
+		t.insert(n, leaf, segments[1:], exact)
+	}
+
+	var child *treeNode
+	switch seg.kind {
+	case segmentStatic:
+		if n.static == nil {
+			n.static = make(map[string]*treeNode)
+		}
+
+		child = n.static[seg.parts[0].static]
+		if child == nil {
+			child = &treeNode{}
+			n.static[seg.parts[0].static] = child
+		}
+	case segmentParam:
+		if n.param == nil {
+			n.param = &treeNode{}
+		}
+
+		child = n.param
+	case segmentMixed:
+		child = n.edge(seg)
+	case segmentSplat:
+		if n.prefix == nil {
+			n.prefix = &treeLeaf{
+				index:  leaf.index,
+				params: leaf.params,
+				splat:  seg.parts[0].name,
+			}
+		}
+
+		return
+	}
+
+	params := leaf.params[:len(leaf.params):len(leaf.params)]
+	t.insert(child, &treeLeaf{index: leaf.index, params: append(params, seg.names()...)}, segments[1:], exact)
+}
+
+// edge return node for mixed segment. Edges with more parts are ranked first,
+// so "file-:name.:ext" is tried before ":name.:ext" and constrained params (":id(\d+)") are last
+func (n *treeNode) edge(seg *segment) *treeNode {
+	for _, e := range n.edges {
+		if e.seg.re.String() == seg.re.String() {
+			return e.node
+		}
+	}
+
+	e := &treeEdge{seg: seg, node: &treeNode{}}
+	n.edges = append(n.edges, e)
+	sort.SliceStable(n.edges, func(i, j int) bool {
+		return len(n.edges[i].seg.parts) > len(n.edges[j].seg.parts)
+	})
+
+	return e.node
+}
+
+// match return index of route matching path and its params
+func (t *tree) match(path string) (int, map[string]string, bool) {
+	leaf, values, rest := t.root.match(splitSegments(path), nil)
+	if leaf == nil {
+		return -1, nil, false
+	}
+
+	params := make(map[string]string)
+	for i, name := range leaf.params {
+		params[name] = values[i]
+	}
+
+	if leaf.splat != "" {
+		params[leaf.splat] = strings.Join(rest, "/")
+	}
+
+	return leaf.index, params, true
+}
+
+func (n *treeNode) match(segments, values []string) (*treeLeaf, []string, []string) {
+	if len(segments) == 0 {
+		if n.exact != nil {
+			return n.exact, values, nil
+		}
+
+		if n.prefix != nil {
+			return n.prefix, values, nil
+		}
+
+		return nil, nil, nil
+	}
+
+	if child, ok := n.static[segments[0]]; ok {
+		leaf, values, rest := child.match(segments[1:], values)
+		if leaf != nil {
+			return leaf, values, rest
+		}
+	}
+
+	for _, e := range n.edges {
+		matches := e.seg.re.FindStringSubmatch(segments[0])
+		if matches == nil {
+			continue
+		}
+
+		edgeValues := values
+		for _, group := range e.seg.groups {
+			edgeValues = append(edgeValues, matches[group])
+		}
+
+		leaf, edgeValues, rest := e.node.match(segments[1:], edgeValues)
+		if leaf != nil {
+			return leaf, edgeValues, rest
+		}
+	}
+
+	if n.param != nil {
+		leaf, values, rest := n.param.match(segments[1:], append(values, segments[0]))
+		if leaf != nil {
+			return leaf, values, rest
+		}
+	}
+
+	if n.prefix != nil {
+		return n.prefix, values, segments
+	}
+
+	return nil, nil, nil
+}
+
+// splitSegments split path to unescaped segments without query and fragment: "/a/b%20c/?d=e" => ["a", "b c"]
+func splitSegments(path string) []string {
+	if i := strings.IndexAny(path, "?#"); i != -1 {
+		path = path[:i]
+	}
+
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segments[i] = unescaped
+		}
+	}
+
+	return segments
+}
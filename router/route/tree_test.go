@@ -0,0 +1,138 @@
+package route
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// buildTree build tree from valid routes
+func buildTree(routes []Route) *tree {
+	t := newTree()
+	for i, route := range routes {
+		p, err := parsePattern(route.Path)
+		if err != nil {
+			panic(err)
+		}
+
+		t.add(i, p, route.Exact)
+	}
+
+	return t
+}
+
+func TestTreeMatch(t *testing.T) {
+	routes := []Route{
+		{Name: "all", Path: "/"},
+		{Name: "user", Path: "/user/:id", Exact: true},
+		{Name: "userEdit", Path: "/user/:id/edit", Exact: true},
+		{Name: "userMe", Path: "/user/me", Exact: true},
+		{Name: "users", Path: "/user", Exact: true},
+		{Name: "docs", Path: "/docs"},
+		{Name: "post", Path: "/:section/:post", Exact: true},
+		{Name: "duplicate", Path: "/user/me", Exact: true},
+	}
+	tree := buildTree(routes)
+
+	data := []struct {
+		path   string
+		name   string
+		params map[string]string
+	}{
+		{path: "/", name: "all", params: map[string]string{}},
+		{path: "/user", name: "users", params: map[string]string{}},
+		{path: "/user/", name: "users", params: map[string]string{}},
+		{path: "/user/me", name: "userMe", params: map[string]string{}},
+		{path: "/user/42", name: "user", params: map[string]string{"id": "42"}},
+		{path: "/user/42?tab=info", name: "user", params: map[string]string{"id": "42"}},
+		{path: "/user/42/edit", name: "userEdit", params: map[string]string{"id": "42"}},
+		{path: "/user/42/delete", name: "all", params: map[string]string{}},
+		{path: "/docs/intro/install", name: "docs", params: map[string]string{}},
+		{path: "/news/gas", name: "post", params: map[string]string{"section": "news", "post": "gas"}},
+		{path: "/user/me/edit", name: "userEdit", params: map[string]string{"id": "me"}},
+	}
+
+	for _, el := range data {
+		index, params, ok := tree.match(el.path)
+		if !ok {
+			t.Errorf("%s: route not found", el.path)
+			continue
+		}
+
+		if routes[index].Name != el.name || !reflect.DeepEqual(params, el.params) {
+			t.Errorf("%s: want: %s %v, got: %s %v", el.path, el.name, el.params, routes[index].Name, params)
+		}
+	}
+
+	if _, _, ok := buildTree(routes[1:5]).match("/docs"); ok {
+		t.Error("route matched without fitting routes")
+	}
+}
+
+func TestTreeOrder(t *testing.T) {
+	routes := []Route{
+		{Name: "param", Path: "/a/:b", Exact: true},
+		{Name: "prefix", Path: "/a"},
+		{Name: "static", Path: "/a/b", Exact: true},
+	}
+
+	for i := 0; i < len(routes); i++ {
+		shifted := append(append([]Route{}, routes[i:]...), routes[:i]...)
+		tree := buildTree(shifted)
+
+		for path, name := range map[string]string{"/a/b": "static", "/a/c": "param", "/a/c/d": "prefix"} {
+			index, _, ok := tree.match(path)
+			if !ok {
+				t.Errorf("%s: route not found", path)
+				continue
+			}
+
+			if shifted[index].Name != name {
+				t.Errorf("%s: want: %s, got: %s", path, name, shifted[index].Name)
+			}
+		}
+	}
+}
+
+func benchmarkRoutes(count int) []Route {
+	var routes []Route
+	for i := 0; i < count/4; i++ {
+		routes = append(routes,
+			Route{Path: fmt.Sprintf("/section%d", i), Exact: true},
+			Route{Path: fmt.Sprintf("/section%d/about", i), Exact: true},
+			Route{Path: fmt.Sprintf("/section%d/:id", i), Exact: true},
+			Route{Path: fmt.Sprintf("/section%d/:id/comments/:comment", i), Exact: true},
+		)
+	}
+
+	return append(routes, Route{Path: "/"})
+}
+
+func BenchmarkTreeMatch(b *testing.B) {
+	for _, count := range []int{10, 100, 500, 1000} {
+		routes := benchmarkRoutes(count)
+
+		b.Run(fmt.Sprintf("build/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				buildTree(routes)
+			}
+		})
+
+		tree := buildTree(routes)
+		last := fmt.Sprintf("/section%d/42/comments/7", count/4-1)
+
+		b.Run(fmt.Sprintf("match/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, _, ok := tree.match(last); !ok {
+					b.Fatal("route not found")
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("fallback/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree.match("/unknown/path")
+			}
+		})
+	}
+}
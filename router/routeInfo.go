@@ -2,8 +2,15 @@ package router
 
 import (
 	"github.com/gascore/gas"
+	"github.com/gascore/std/router/route"
 )
 
+// Bind decode route params and queries to struct v points to by "param" and "query" field tags.
+// See route.Bind
+func (info *RouteInfo) Bind(v interface{}) error {
+	return route.Bind(v, info.Params, info.Query)
+}
+
 func (ctx *Ctx) CustomPush(path string, replace bool) {
 	if ctx.Settings.GetUserConfirmation != nil && ctx.Settings.GetUserConfirmation() {
 		return
@@ -15,7 +22,23 @@ func (ctx *Ctx) CustomPush(path string, replace bool) {
 }
 
 func (ctx *Ctx) CustomPushDynamic(name string, params, queries gas.Map, replace bool) {
-	ctx.CustomPush(ctx.fillPath(name, params, queries), replace)
+	path, ok := ctx.fillPath(name, params, queries)
+	if !ok {
+		return
+	}
+
+	ctx.CustomPush(path, replace)
+}
+
+// CustomPushStruct push user to route with params and queries encoded from struct. See route.Encode
+func (ctx *Ctx) CustomPushStruct(name string, v interface{}, replace bool) {
+	path, err := ctx.table.PathStruct(name, v)
+	if err != nil {
+		ctx.This.c.WarnError(err)
+		return
+	}
+
+	ctx.CustomPush(path, replace)
 }
 
 // Push push user to another page
@@ -38,6 +61,16 @@ func (ctx *Ctx) ReplaceDynamic(name string, params, queries gas.Map) {
 	ctx.CustomPushDynamic(name, params, queries, true)
 }
 
+// PushStruct push user to another route with params and queries from struct
+func (ctx *Ctx) PushStruct(name string, v interface{}) {
+	ctx.CustomPushStruct(name, v, false)
+}
+
+// ReplaceStruct replace current page with route with params and queries from struct
+func (ctx *Ctx) ReplaceStruct(name string, v interface{}) {
+	ctx.CustomPushStruct(name, v, true)
+}
+
 func (ctx Ctx) link(path string, push func(gas.Event), e gas.External) *gas.Element {
 	var attrs gas.Map
 	if e.Attrs == nil {
@@ -78,12 +111,28 @@ func (ctx *Ctx) Link(to string, e gas.External) *gas.Element {
 		e)
 }
 
-//LinkWithParams create link to route with queries and params
+// LinkWithParams create link to route with queries and params
 func (ctx *Ctx) LinkWithParams(name string, params, queries gas.Map, e gas.External) *gas.Element {
+	path, _ := ctx.fillPath(name, params, queries)
 	return ctx.link(
-		ctx.fillPath(name, params, queries),
+		path,
 		func(e gas.Event) {
 			ctx.PushDynamic(name, params, queries)
 		},
 		e)
 }
+
+// LinkStruct create link to route with queries and params from struct
+func (ctx *Ctx) LinkStruct(name string, v interface{}, e gas.External) *gas.Element {
+	path, err := ctx.table.PathStruct(name, v)
+	if err != nil {
+		ctx.This.c.WarnError(err)
+	}
+
+	return ctx.link(
+		path,
+		func(e gas.Event) {
+			ctx.PushStruct(name, v)
+		},
+		e)
+}
@@ -1,11 +1,12 @@
 package router
 
 import (
-	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/gascore/dom"
 	"github.com/gascore/gas"
+	"github.com/gascore/std/router/route"
 )
 
 // ChangeRouteEvent name for custom event
@@ -40,8 +41,8 @@ type Ctx struct {
 
 	Before, After func(to, from *RouteInfo) error
 
-	notFound      *gas.C // rendered user not found page
-	renderedPaths gas.Map
+	notFound *gas.C // rendered user not found page
+	table    *route.Table
 }
 
 // Settings router settings
@@ -56,6 +57,7 @@ type Settings struct {
 
 	NotFound func() *gas.Component
 
+	// Deprecated: not used, paths are filled without limit of params
 	MaxRouteParams int
 }
 
@@ -65,7 +67,10 @@ type RouteInfo struct {
 	URL  string
 
 	Params      gas.Map // /links/:foo => {"foo": "bar"}
-	QueryParams gas.Map // /links?foo=bar => {"foo": "bar"}
+	QueryParams gas.Map // /links?foo=bar => {"foo": "bar"}, first value of each query
+
+	Query    url.Values // /links?tag=a&tag=b => {"tag": ["a", "b"]}
+	Fragment string     // /links#foo => "foo"
 
 	Route Route
 
@@ -98,39 +103,48 @@ func (ctx *Ctx) Init() {
 		ctx.Settings.BaseName = "#" + ctx.Settings.HashSuffix + ctx.Settings.BaseName
 	}
 
-	if ctx.Settings.MaxRouteParams == 0 {
-		ctx.Settings.MaxRouteParams = 64
-	}
-
-	ctx.renderedPaths = make(gas.Map)
-
 	var newRoutes []Route
-	for _, route := range ctx.Routes {
-		if len(route.RedirectName) != 0 {
-			if route.RedirectParams == nil {
-				route.RedirectParams = make(gas.Map)
+	for _, r := range ctx.Routes {
+		if len(r.RedirectName) != 0 {
+			if r.RedirectParams == nil {
+				r.RedirectParams = make(gas.Map)
 			}
 
-			if route.RedirectQueries == nil {
-				route.RedirectQueries = make(gas.Map)
+			if r.RedirectQueries == nil {
+				r.RedirectQueries = make(gas.Map)
 			}
 		}
 
-		newRoutes = append(newRoutes, decomposeRouteChildes(route)...)
+		newRoutes = append(newRoutes, decomposeRouteChildes(r)...)
 	}
 	ctx.Routes = newRoutes
+
+	routes := make([]route.Route, len(ctx.Routes))
+	for i, r := range ctx.Routes {
+		routes[i] = route.Route{Name: r.Name, Path: r.Path, Exact: r.Exact}
+	}
+	table, err := route.New(routes)
+	if err != nil {
+		dom.ConsoleError(err.Error())
+	}
+	ctx.table = table
 }
 
-func decomposeRouteChildes(route Route) []Route {
+// Table return compiled routes table. Available after Init
+func (ctx *Ctx) Table() *route.Table {
+	return ctx.table
+}
+
+func decomposeRouteChildes(parent Route) []Route {
 	var newRoutes []Route
-	for _, c := range route.Childes {
-		if route.Before != nil {
+	for _, c := range parent.Childes {
+		if parent.Before != nil {
 			if c.Before == nil {
-				c.Before = route.Before
+				c.Before = parent.Before
 			} else {
 				cBefore := c.Before
 				c.Before = func(info *MiddlewareInfo) (bool, error) {
-					stop, err := route.Before(info)
+					stop, err := parent.Before(info)
 					if err != nil {
 						return stop, err
 					}
@@ -149,13 +163,13 @@ func decomposeRouteChildes(route Route) []Route {
 			}
 		}
 
-		if route.After != nil {
+		if parent.After != nil {
 			if c.After == nil {
-				c.After = route.After
+				c.After = parent.After
 			} else {
 				cAfter := c.After
 				c.After = func(info *MiddlewareInfo) (bool, error) {
-					stop, err := route.After(info)
+					stop, err := parent.After(info)
 					if err != nil {
 						return stop, err
 					}
@@ -174,7 +188,7 @@ func decomposeRouteChildes(route Route) []Route {
 			}
 		}
 
-		c.Path = route.Path + c.Path
+		c.Path = parent.Path + c.Path
 
 		if len(c.Childes) != 0 {
 			newRoutes = append(newRoutes, decomposeRouteChildes(c)...)
@@ -183,8 +197,8 @@ func decomposeRouteChildes(route Route) []Route {
 		newRoutes = append(newRoutes, c)
 	}
 
-	route.Childes = []Route{}
-	newRoutes = append(newRoutes, route)
+	parent.Childes = []Route{}
+	newRoutes = append(newRoutes, parent)
 
 	return newRoutes
 }
@@ -258,84 +272,90 @@ func (root *routerComponent) findRoute(currentPath string) *gas.Component {
 		return root.lastItem
 	}
 
-	for _, route := range ctx.Routes {
-		routeIsFits, params, queries, err := ctx.matchPath(currentPath, route)
-		if err != nil {
-			root.c.ConsoleError(fmt.Sprintf("error in router: %s", err.Error()))
-			return nil
-		}
+	path, query, fragment, err := route.ParseURL(currentPath)
+	if err != nil {
+		root.c.WarnError(err)
+	}
+	queries := route.First(query)
 
-		if !routeIsFits {
-			continue
-		}
+	match, ok := ctx.table.Match(path)
+	if !ok {
+		return ctx.notFound
+	}
+	matched := ctx.Routes[match.Index]
 
-		to := &RouteInfo{
-			Name: route.Name,
-			URL:  currentPath,
+	to := &RouteInfo{
+		Name: matched.Name,
+		URL:  currentPath,
 
-			Params:      params,
-			QueryParams: queries,
+		Params:      match.Params,
+		QueryParams: queries,
+		Query:       query,
+		Fragment:    fragment,
 
-			Route: route,
+		Route: matched,
 
-			Ctx: ctx,
-		}
+		Ctx: ctx,
+	}
 
-		if ctx.Before != nil {
-			err := ctx.Before(to, root.lastRouteInfo)
-			if err != nil {
-				root.c.ConsoleError(err.Error())
-				return root.lastItem // don't update route
-			}
+	if ctx.Before != nil {
+		err := ctx.Before(to, root.lastRouteInfo)
+		if err != nil {
+			root.c.ConsoleError(err.Error())
+			return root.lastItem // don't update route
 		}
+	}
 
-		if route.Before != nil {
-			var newPath string
-			newReplace := true
+	if matched.Before != nil {
+		var newPath string
+		newReplace := true
 
-			stop, err := route.Before(&MiddlewareInfo{
-				To:   to,
-				From: root.lastRouteInfo,
-				Change: func(path string, replace bool) {
+		stop, err := matched.Before(&MiddlewareInfo{
+			To:   to,
+			From: root.lastRouteInfo,
+			Change: func(path string, replace bool) {
+				newPath = path
+				newReplace = replace
+			},
+			ChangeDynamic: func(name string, params, queries gas.Map, replace bool) {
+				if path, ok := ctx.fillPath(name, params, queries); ok {
 					newPath = path
 					newReplace = replace
-				},
-				ChangeDynamic: func(name string, params, queries gas.Map, replace bool) {
-					newPath = ctx.fillPath(name, params, queries)
-					newReplace = replace
-				},
-			})
-			if err != nil {
-				root.c.ConsoleError(err.Error())
-			}
-
-			if len(newPath) != 0 && stop {
-				ctx.ChangeRoute(newPath, newReplace)
-				return root.findRoute(newPath)
-			}
+				}
+			},
+		})
+		if err != nil {
+			root.c.ConsoleError(err.Error())
 		}
 
-		if len(route.Redirect) != 0 {
-			ctx.ChangeRoute(route.Redirect, true)
-			return root.findRoute(route.Redirect)
+		if len(newPath) != 0 && stop {
+			ctx.ChangeRoute(newPath, newReplace)
+			return root.findRoute(newPath)
 		}
+	}
+
+	if len(matched.Redirect) != 0 {
+		ctx.ChangeRoute(matched.Redirect, true)
+		return root.findRoute(matched.Redirect)
+	}
 
-		if len(route.RedirectName) != 0 {
-			path := ctx.fillPath(route.RedirectName, route.RedirectParams, route.RedirectQueries)
-			ctx.ChangeRoute(path, true)
-			return root.findRoute(path)
+	if len(matched.RedirectName) != 0 {
+		path, ok := ctx.fillPath(matched.RedirectName, matched.RedirectParams, matched.RedirectQueries)
+		if !ok {
+			return ctx.notFound
 		}
 
-		root.lastRouteInfo = to
-		root.lastRoute = currentPath
+		ctx.ChangeRoute(path, true)
+		return root.findRoute(path)
+	}
 
-		root.lastItem = route.Component(to)
-		root.lastItem.NotPointer = true
+	root.lastRouteInfo = to
+	root.lastRoute = currentPath
 
-		return root.lastItem
-	}
+	root.lastItem = matched.Component(to)
+	root.lastItem.NotPointer = true
 
-	return ctx.notFound
+	return root.lastItem
 }
 
 func (root *routerComponent) update() {
@@ -385,5 +405,10 @@ func (ctx *Ctx) ChangeRoute(path string, replace bool) {
 
 // ChangeRouteDynamic change current route with params and queries
 func (ctx *Ctx) ChangeRouteDynamic(name string, params, queries gas.Map, replace bool) {
-	ctx.ChangeRoute(ctx.fillPath(name, params, queries), replace)
+	path, ok := ctx.fillPath(name, params, queries)
+	if !ok {
+		return
+	}
+
+	ctx.ChangeRoute(path, replace)
 }
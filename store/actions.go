@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// ActionsKey Data key with actions states (map[string]ActionState).
+// Use it in components: s.Get("actions[fetchUser].Pending")
+const ActionsKey = "actions"
+
+// Action asynchronous event handler. Action can commit partial updates many times while running.
+// Action must stop when ctx is done, commits after cancellation are rejected
+type Action func(ctx context.Context, s *Store, commit Commit, values ...interface{}) error
+
+// Commit apply updatesMap to store as event with action name
+type Commit func(updatesMap map[string]interface{}) error
+
+// ActionState action state stored in Data. It contains only serializable values, so it can be persisted and synced
+type ActionState struct {
+	Pending bool   // true while at least one dispatch of action is running
+	Err     string // last action error message, empty if action succeeded
+}
+
+// Task running action
+type Task struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// Cancel cancel action context
+func (t *Task) Cancel() {
+	t.cancel()
+}
+
+// Done return channel closed when action is finished
+func (t *Task) Done() <-chan struct{} {
+	return t.done
+}
+
+// Wait wait for action finishing and return it's error
+func (t *Task) Wait() error {
+	<-t.done
+	return t.err
+}
+
+// initActions add actions states to Data or Schema. Return error if Data or Schema already has ActionsKey field
+func (s *Store) initActions() error {
+	if _, ok := s.Data[ActionsKey]; ok {
+		return fmt.Errorf("actions key %s is already used in Data", ActionsKey)
+	}
+
+	if _, ok := s.Schema[ActionsKey]; ok {
+		return fmt.Errorf("actions key %s is already used in Schema", ActionsKey)
+	}
+
+	states := make(map[string]ActionState)
+	for name := range s.Actions {
+		states[name] = ActionState{}
+	}
+
+	if s.Schema != nil {
+		s.Schema[ActionsKey] = Field{Default: states}
+		return nil
+	}
+
+	if s.Data == nil {
+		s.Data = make(map[string]interface{})
+	}
+
+	s.Data[ActionsKey] = states
+	return nil
+}
+
+// Dispatch run action in new goroutine
+func (s *Store) Dispatch(name string, values ...interface{}) (*Task, error) {
+	return s.DispatchContext(context.Background(), name, values...)
+}
+
+// DispatchContext run action in new goroutine with parent context
+func (s *Store) DispatchContext(parent context.Context, name string, values ...interface{}) (*Task, error) {
+	s.mu.RLock()
+	action, ok := s.Actions[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("undefined action name: %s", name)
+	}
+
+	if action == nil {
+		return nil, fmt.Errorf("invalid action: %s", name)
+	}
+
+	err := s.setActionState(name, 1, nil, values)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	task := &Task{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	commit := func(updatesMap map[string]interface{}) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		s.emitMu.Lock()
+		defer s.emitMu.Unlock()
+
+		// action could be canceled while waiting for other events
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return s.commit(name, updatesMap, values)
+	}
+
+	go func() {
+		defer close(task.done)
+		defer cancel()
+
+		err := action(ctx, s, commit, values...)
+		if err == nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+
+		task.err = err
+		stateErr := s.setActionState(name, -1, err, values)
+		if task.err == nil {
+			task.err = stateErr
+		}
+	}()
+
+	return task, nil
+}
+
+// setActionState change count of running dispatches of action by delta and commit action state
+func (s *Store) setActionState(name string, delta int, err error, values []interface{}) error {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	if s.actionsRunning == nil {
+		s.actionsRunning = make(map[string]int)
+	}
+
+	s.actionsRunning[name] += delta
+
+	state := ActionState{Pending: s.actionsRunning[name] > 0}
+	if err != nil {
+		state.Err = err.Error()
+	}
+
+	commitErr := s.commit(name, map[string]interface{}{ActionsKey + "[" + name + "]": state}, values)
+	if commitErr != nil && delta > 0 {
+		s.actionsRunning[name] -= delta // action won't be run
+	}
+
+	return commitErr
+}
@@ -0,0 +1,175 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestActions(t *testing.T) {
+	loaded := make(chan struct{})
+
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"users":  []string{},
+			"status": "",
+		},
+		Actions: map[string]Action{
+			"fetchUsers": func(ctx context.Context, s *Store, commit Commit, values ...interface{}) error {
+				err := commit(map[string]interface{}{"status": "loading"})
+				if err != nil {
+					return err
+				}
+
+				<-loaded
+				return commit(map[string]interface{}{
+					"users":  []string{"Artem", "Elen"},
+					"status": "done",
+				})
+			},
+			"wait": func(ctx context.Context, s *Store, commit Commit, values ...interface{}) error {
+				<-ctx.Done()
+				return commit(map[string]interface{}{"status": "canceled"})
+			},
+			"fail": func(ctx context.Context, s *Store, commit Commit, values ...interface{}) error {
+				return errors.New("network error")
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	task, err := s.Dispatch("fetchUsers")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if s.Get("actions[fetchUsers].Pending") != true {
+		t.Error("action isn't pending")
+	}
+
+	close(loaded)
+	if err := task.Wait(); err != nil {
+		t.Errorf("unexpected action error: %s", err.Error())
+		return
+	}
+
+	if s.Get("status") != "done" || len(s.Get("users").([]string)) != 2 || s.Get("actions[fetchUsers].Pending") != false {
+		t.Errorf("invalid data after action: %v", s.Data)
+	}
+
+	task, err = s.Dispatch("wait")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	task.Cancel()
+	if err := task.Wait(); err != context.Canceled {
+		t.Errorf("invalid error for canceled action: %v", err)
+	}
+
+	if s.Get("status") != "done" {
+		t.Error("canceled action committed updates")
+	}
+
+	task, err = s.Dispatch("fail")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if task.Wait() == nil || s.Get("actions[fail].Err") != "network error" {
+		t.Error("action error wasn't reported")
+	}
+
+	if _, err := s.Dispatch("undefined"); err == nil {
+		t.Error("expected error for undefined action")
+	}
+
+	noop := func(ctx context.Context, s *Store, commit Commit, values ...interface{}) error { return nil }
+	for _, el := range []*Store{
+		{Data: map[string]interface{}{ActionsKey: 1}, Actions: map[string]Action{"noop": noop}},
+		{Schema: map[string]Field{ActionsKey: {Default: 1}}, Actions: map[string]Action{"noop": noop}},
+	} {
+		if _, err := New(el); err == nil {
+			t.Error("expected error for field with actions key")
+		}
+	}
+}
+
+func TestActionsOverlapping(t *testing.T) {
+	release := make(chan struct{})
+
+	s, err := New(&Store{
+		Actions: map[string]Action{
+			"load": func(ctx context.Context, s *Store, commit Commit, values ...interface{}) error {
+				if values[0] == "slow" {
+					<-release
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	slow, _ := s.Dispatch("load", "slow")
+	fast, _ := s.Dispatch("load", "fast")
+
+	if err := fast.Wait(); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	if s.Get("actions[load].Pending") != true {
+		t.Error("action isn't pending while other dispatch is running")
+	}
+
+	close(release)
+	slow.Wait()
+
+	if s.Get("actions[load].Pending") != false {
+		t.Error("action is pending after all dispatches finished")
+	}
+}
+
+func TestActionsPersist(t *testing.T) {
+	storage := NewMemoryStorage()
+	newStore := func() (*Store, error) {
+		p := &Persist{Storage: storage}
+		return New(&Store{
+			Data: map[string]interface{}{"status": ""},
+			Actions: map[string]Action{
+				"fail": func(ctx context.Context, s *Store, commit Commit, values ...interface{}) error {
+					return errors.New("network error")
+				},
+			},
+			OnCreate:  []OnCreateHook{p.OnCreate},
+			AfterEmit: []AfterEmitHook{p.AfterEmit},
+		})
+	}
+
+	s, err := newStore()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	task, _ := s.Dispatch("fail")
+	task.Wait()
+
+	s, err = newStore()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if s.Get("actions[fail].Err") != "network error" {
+		t.Errorf("invalid loaded action state: %#v", s.Get("actions[fail]"))
+	}
+}
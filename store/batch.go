@@ -0,0 +1,84 @@
+package store
+
+// BatchEvent event name for History entries recorded by Store.Batch
+const BatchEvent = "store/batch"
+
+// Batch group of events committed at once
+type Batch struct {
+	s *Store
+
+	events []emitted
+	keys   []string
+	prev   map[string]interface{}
+
+	err error // first events error
+}
+
+// Emit runs event from Store handlers. Handlers see Data changed by previous batch events,
+// but subscribers and AfterEmit hooks will be called only after batch end
+func (b *Batch) Emit(query string, values ...interface{}) error {
+	e := &Event{Name: query, Values: values}
+	updatesMap, err := b.s.handle(e)
+	if err == nil && updatesMap == nil {
+		return nil
+	}
+
+	if err == nil {
+		_, err = b.apply(e.Name, updatesMap)
+	}
+
+	if err != nil {
+		if b.err == nil {
+			b.err = err
+		}
+
+		return err
+	}
+
+	b.events = append(b.events, emitted{event: e.Name, updatesMap: updatesMap, values: e.Values})
+
+	return nil
+}
+
+// apply apply updatesMap to Data remembering previous values of changed fields
+func (b *Batch) apply(eventName string, updatesMap map[string]interface{}) (map[string]interface{}, error) {
+	keys, prev, err := b.s.apply(eventName, updatesMap)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range prev {
+		if _, ok := b.prev[name]; !ok {
+			b.prev[name] = value
+		}
+	}
+
+	b.keys = append(b.keys, keys...)
+	return prev, nil
+}
+
+// Batch run fn and commit all events emitted by Batch.Emit in one Data update and one re-render.
+// If fn, one of events, subscriber updates or AfterEmit hooks failed, all events will be rolled back
+func (s *Store) Batch(fn func(b *Batch) error) error {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	b := &Batch{s: s, prev: make(map[string]interface{})}
+
+	err := fn(b)
+	if err == nil {
+		err = b.err
+	}
+
+	if err != nil {
+		s.commitHooks = nil
+		s.restore(b.keys, b.prev)
+		return err
+	}
+
+	if len(b.events) == 0 {
+		return nil
+	}
+
+	return s.publish(b.events, b.keys, b.prev)
+}
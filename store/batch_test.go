@@ -0,0 +1,117 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gascore/std/store/internal/fake"
+)
+
+func newTransactionStore(afterEmitErr *error) (*Store, error) {
+	return New(&Store{
+		Data: map[string]interface{}{
+			"counter": 0,
+			"title":   "",
+		},
+		Handlers: map[string]Handler{
+			"inc": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": s.Get("counter").(int) + 1}, nil
+			},
+			"broken": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": 10, "title": 1}, nil
+			},
+			"rename": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"title": values[0]}, nil
+			},
+		},
+		AfterEmit: []AfterEmitHook{
+			func(s *Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+				return *afterEmitErr
+			},
+		},
+	})
+}
+
+func TestAtomicEmit(t *testing.T) {
+	var afterEmitErr error
+	s, err := newTransactionStore(&afterEmitErr)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Emit("broken"); err == nil {
+		t.Error("expected error for invalid updatesMap")
+	}
+
+	if s.Get("counter") != 0 {
+		t.Errorf("invalid updatesMap was partly applied: %v", s.Data)
+	}
+
+	afterEmitErr = errors.New("can't save data")
+	if err := s.Emit("inc"); err != afterEmitErr {
+		t.Errorf("AfterEmit error wasn't returned: %v", err)
+	}
+
+	if s.Get("counter") != 0 {
+		t.Errorf("data wasn't rolled back after AfterEmit error: %v", s.Data)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	var afterEmitErr error
+	s, err := newTransactionStore(&afterEmitErr)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	c := fake.NewComponent(nil)
+	s.RegisterComponent(c.C)
+	mountComponent(t, c.C)
+
+	err = s.Batch(func(b *Batch) error {
+		for i := 0; i < 3; i++ {
+			if err := b.Emit("inc"); err != nil {
+				return err
+			}
+		}
+
+		return b.Emit("rename", "batch")
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if s.Get("counter") != 3 || s.Get("title") != "batch" {
+		t.Errorf("invalid data after batch: %v", s.Data)
+	}
+
+	if c.Renders() != 2 {
+		t.Errorf("invalid renders count want: 2, got: %d", c.Renders())
+	}
+
+	// failed event rolls back whole batch
+	err = s.Batch(func(b *Batch) error {
+		b.Emit("inc")
+		b.Emit("broken")
+		return nil
+	})
+	if err == nil {
+		t.Error("expected error for batch with invalid event")
+	}
+
+	afterEmitErr = errors.New("can't save data")
+	err = s.Batch(func(b *Batch) error {
+		b.Emit("inc")
+		return b.Emit("rename", "failed")
+	})
+	if err != afterEmitErr {
+		t.Errorf("AfterEmit error wasn't returned: %v", err)
+	}
+
+	if s.Get("counter") != 3 || s.Get("title") != "batch" {
+		t.Errorf("batch wasn't rolled back: %v", s.Data)
+	}
+}
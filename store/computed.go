@@ -0,0 +1,89 @@
+package store
+
+import (
+	"fmt"
+)
+
+// Computed derived value. Value is cached until one of Deps (Data keys or other Computed names) changes.
+// Computed values are readable by Store.Get: s.Get("total"), s.Get("filteredTodos[0].Title")
+type Computed struct {
+	Deps []string
+	Get  func(s *Store) (interface{}, error)
+}
+
+// initComputed check computed fields and create cache
+func (s *Store) initComputed() error {
+	for name, computed := range s.Computed {
+		if computed.Get == nil {
+			return fmt.Errorf("computed '%s': Get is nil", name)
+		}
+
+		if _, ok := s.Data[name]; ok {
+			return fmt.Errorf("computed '%s': Data has field with same name", name)
+		}
+	}
+
+	s.computedCache = make(map[string]interface{})
+	s.computedGen = make(map[string]int)
+
+	return nil
+}
+
+// computed return cached value or compute it
+func (s *Store) computed(name string) (interface{}, error) {
+	s.computedMu.Lock()
+	value, ok := s.computedCache[name]
+	gen := s.computedGen[name]
+	s.computedMu.Unlock()
+	if ok {
+		return value, nil
+	}
+
+	// compute without lock, because Get can read other computed values
+	value, err := s.Computed[name].Get(s)
+	if err != nil {
+		return nil, fmt.Errorf("computed '%s': %s", name, err.Error())
+	}
+
+	s.computedMu.Lock()
+	if s.computedGen[name] == gen { // deps weren't changed while computing
+		s.computedCache[name] = value
+	}
+	s.computedMu.Unlock()
+
+	return value, nil
+}
+
+// invalidateComputed drop cache of computed values depending on changed keys. Returns invalidated names
+func (s *Store) invalidateComputed(changed []string) []string {
+	if len(s.Computed) == 0 {
+		return nil
+	}
+
+	s.computedMu.Lock()
+	defer s.computedMu.Unlock()
+
+	var invalidated []string
+	isInvalidated := make(map[string]bool)
+
+	// computed values can depend on other computed values
+	for keys := changed; len(keys) != 0; {
+		var next []string
+		for name, computed := range s.Computed {
+			if isInvalidated[name] || !keysOverlap(computed.Deps, keys) {
+				continue
+			}
+
+			isInvalidated[name] = true
+			next = append(next, name)
+
+			delete(s.computedCache, name)
+			s.computedGen[name]++
+		}
+
+		invalidated = append(invalidated, next...)
+		keys = next
+	}
+
+	return invalidated
+}
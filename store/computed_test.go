@@ -0,0 +1,86 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/gascore/std/store/internal/fake"
+)
+
+func TestComputed(t *testing.T) {
+	var totalCalls, labelCalls int
+
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"prices":   []int{1, 2, 3},
+			"currency": "$",
+			"title":    "",
+		},
+		Computed: map[string]Computed{
+			"total": {
+				Deps: []string{"prices"},
+				Get: func(s *Store) (interface{}, error) {
+					totalCalls++
+
+					var total int
+					for _, price := range s.Get("prices").([]int) {
+						total += price
+					}
+					return total, nil
+				},
+			},
+			"label": {
+				Deps: []string{"total", "currency"},
+				Get: func(s *Store) (interface{}, error) {
+					labelCalls++
+					return map[string]interface{}{"total": s.Get("total"), "currency": s.Get("currency")}, nil
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	c := fake.NewComponent(nil)
+	s.RegisterComponentWithKeys(c.C, "label")
+	mountComponent(t, c.C)
+
+	data := []struct {
+		updates                map[string]interface{}
+		total                  int
+		totalCalls, labelCalls int
+		renders                int
+	}{
+		{updates: nil, total: 6, totalCalls: 1, labelCalls: 1, renders: 1},
+		{updates: map[string]interface{}{"title": "shop"}, total: 6, totalCalls: 1, labelCalls: 1, renders: 1},
+		{updates: map[string]interface{}{"prices[0]": 4}, total: 9, totalCalls: 2, labelCalls: 2, renders: 2},
+		{updates: map[string]interface{}{"currency": "€"}, total: 9, totalCalls: 2, labelCalls: 3, renders: 3},
+	}
+
+	for _, el := range data {
+		if el.updates != nil {
+			if err := s.UpdateStore(el.updates); err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+				return
+			}
+		}
+
+		if s.Get("label.total") != el.total || s.Get("total") != el.total {
+			t.Errorf("invalid total after %v want: %d, got: %v", el.updates, el.total, s.Get("label.total"))
+		}
+
+		if totalCalls != el.totalCalls || labelCalls != el.labelCalls {
+			t.Errorf("invalid computing calls after %v want: %d %d, got: %d %d", el.updates,
+				el.totalCalls, el.labelCalls, totalCalls, labelCalls)
+		}
+
+		if c.Renders() != el.renders {
+			t.Errorf("invalid renders count after %v want: %d, got: %d", el.updates, el.renders, c.Renders())
+		}
+	}
+
+	if err := s.UpdateStore(map[string]interface{}{"total": 1}); err == nil {
+		t.Error("expected error for computed value update")
+	}
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gascore/gas"
+	"github.com/gascore/std/store/internal/fake"
+)
+
+func TestConcurrentEmit(t *testing.T) {
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"counter": 0,
+			"user":    map[string]int{"visits": 0},
+		},
+		Handlers: map[string]Handler{
+			"inc": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{
+					"counter":     s.Get("counter").(int) + 1,
+					"user.visits": s.Get("user.visits").(int) + 1,
+				}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	c := s.RegisterComponentTracked(&gas.C{RC: gas.GetEmptyRenderCore()}, &trackedCountingRoot{render: func(t *Tracker) []interface{} {
+		return []interface{}{t.Get("counter")}
+	}})
+	mountComponent(t, c)
+
+	const goroutines, emits = 16, 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < emits; j++ {
+				if err := s.Emit("inc"); err != nil {
+					t.Errorf("unexpected error: %s", err.Error())
+					return
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < emits; j++ {
+				s.Get("counter")
+				s.Get("user.visits")
+			}
+		}()
+	}
+
+	// components can be registered and destroyed while events are processing
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < emits; j++ {
+			other := fake.NewComponent(nil)
+			s.RegisterComponentWithKeys(other.C, "user")
+			mountComponent(t, other.C)
+
+			if err := other.Destroy(); err != nil {
+				t.Errorf("unexpected error in BeforeDestroy: %s", err.Error())
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	if s.Get("counter") != goroutines*emits || s.Get("user.visits") != goroutines*emits {
+		t.Errorf("invalid counter want: %d, got: %v and %v", goroutines*emits, s.Get("counter"), s.Get("user.visits"))
+	}
+}
@@ -0,0 +1,162 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/gascore/gas"
+)
+
+// Selector select value component depends on. Selector must read Data only by Tracker.Get
+type Selector func(t *Tracker) interface{}
+
+// Equal compare selected values
+type Equal func(a, b interface{}) bool
+
+// Binding component connected to store by Selector
+type Binding struct {
+	s        *Store
+	c        *gas.Component
+	selector Selector
+	equal    Equal
+
+	mu    sync.Mutex
+	value interface{}
+	keys  []string // keys read by selector
+}
+
+// Connect bind component to selector result. Component is re-rendered only if selector result changed (by equal).
+// If equal is nil reflect.DeepEqual is used. Selector is called again only if keys it read were changed.
+//
+// Use Binding.Value or Binding.Load in component Render:
+//
+// b := s.Connect(c, func(t *store.Tracker) interface{} { return t.Get("todos") }, nil)
+// ...
+// var todos []Todo
+// err := b.Load(&todos)
+func (s *Store) Connect(c *gas.C, selector Selector, equal Equal) *Binding {
+	if equal == nil {
+		equal = reflect.DeepEqual
+	}
+
+	b := &Binding{s: s, c: c, selector: selector, equal: equal}
+	b.value, b.keys = b.selectValue()
+
+	created := c.Hooks.Created
+	c.Hooks.Created = func() error {
+		// store could be changed between Connect and Created
+		b.mu.Lock()
+		b.value, b.keys = b.selectValue()
+		b.mu.Unlock()
+
+		s.mu.Lock()
+		s.bindings = append(s.bindings, b)
+		s.mu.Unlock()
+
+		if created != nil {
+			return created()
+		}
+
+		return nil
+	}
+
+	willDestroy := c.Hooks.BeforeDestroy
+	c.Hooks.BeforeDestroy = func() error {
+		s.removeBinding(b)
+
+		if willDestroy != nil {
+			return willDestroy()
+		}
+
+		return nil
+	}
+
+	return b
+}
+
+// Value return last selected value
+func (b *Binding) Value() interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.value
+}
+
+// Load set last selected value to value ptr points to.
+// Return error if value can't be assigned to ptr element type
+func (b *Binding) Load(ptr interface{}) error {
+	dst := reflect.ValueOf(ptr)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return fmt.Errorf("invalid pointer: %T", ptr)
+	}
+
+	value := b.Value()
+	if value == nil {
+		dst.Elem().Set(reflect.Zero(dst.Elem().Type()))
+		return nil
+	}
+
+	src := reflect.ValueOf(value)
+	if !src.Type().AssignableTo(dst.Elem().Type()) {
+		return fmt.Errorf("uncompared types: %T and %s", value, dst.Elem().Type().String())
+	}
+
+	dst.Elem().Set(src)
+	return nil
+}
+
+// refresh select value again if one of changed keys was read by selector. Return true if value was changed
+func (b *Binding) refresh(changed []string) bool {
+	b.mu.Lock()
+	keys := b.keys
+	b.mu.Unlock()
+
+	if !keysOverlap(keys, changed) {
+		return false
+	}
+
+	value, keys := b.selectValue()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.keys = keys
+	if b.equal(b.value, value) {
+		return false
+	}
+
+	b.value = value
+	return true
+}
+
+func (b *Binding) selectValue() (value interface{}, keys []string) {
+	keys = b.s.track(func(t *Tracker) {
+		value = b.selector(t)
+	})
+
+	return value, keys
+}
+
+// removeBinding remove binding from store bindings
+func (s *Store) removeBinding(b *Binding) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, el := range s.bindings {
+		if el == b {
+			s.bindings = append(s.bindings[:i], s.bindings[i+1:]...)
+			return
+		}
+	}
+}
+
+func containsComponent(components []*gas.Component, c *gas.Component) bool {
+	for _, el := range components {
+		if el == c {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/gascore/std/store/internal/fake"
+)
+
+func TestConnect(t *testing.T) {
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"todos":  []string{"a"},
+			"filter": "",
+			"title":  "",
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	var b *Binding
+	var rendered []string
+	c := fake.NewComponent(func() []interface{} {
+		if err := b.Load(&rendered); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+		return nil
+	})
+
+	selects := 0
+	b = s.Connect(c.C, func(tr *Tracker) interface{} {
+		selects++
+
+		var out []string
+		for _, todo := range tr.Get("todos").([]string) {
+			if todo != tr.Get("filter") {
+				out = append(out, todo)
+			}
+		}
+		return out
+	}, nil)
+	mountComponent(t, c.C)
+
+	data := []struct {
+		updates          map[string]interface{}
+		renders, selects int
+		rendered         []string
+	}{
+		{updates: map[string]interface{}{"title": "hello"}, renders: 1, selects: 2, rendered: []string{"a"}},
+		{updates: map[string]interface{}{"todos": []string{"a", "b"}}, renders: 2, selects: 3, rendered: []string{"a", "b"}},
+		{updates: map[string]interface{}{"filter": "c"}, renders: 2, selects: 4, rendered: []string{"a", "b"}},
+		{updates: map[string]interface{}{"filter": "a"}, renders: 3, selects: 5, rendered: []string{"b"}},
+	}
+
+	for _, el := range data {
+		if err := s.UpdateStore(el.updates); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+
+		if c.Renders() != el.renders || selects != el.selects || len(rendered) != len(el.rendered) {
+			t.Errorf("invalid state after %v want: %d %d %v, got: %d %d %v", el.updates,
+				el.renders, el.selects, el.rendered, c.Renders(), selects, rendered)
+		}
+	}
+
+	var wrong map[string]int
+	if err := b.Load(&wrong); err == nil {
+		t.Error("expected error for invalid Load type")
+	}
+
+	if err := c.Destroy(); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	s.UpdateStore(map[string]interface{}{"filter": "b"})
+	if c.Renders() != 3 || selects != 5 {
+		t.Error("destroyed component is still connected")
+	}
+}
+
+func TestConnectChangedBeforeMount(t *testing.T) {
+	s, err := New(&Store{Data: map[string]interface{}{"title": "a"}})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	c := fake.NewComponent(nil)
+	b := s.Connect(c.C, func(tr *Tracker) interface{} { return tr.Get("title") }, nil)
+
+	// binding isn't registered yet, so this change must be picked in Created
+	if err := s.UpdateStore(map[string]interface{}{"title": "b"}); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	mountComponent(t, c.C)
+
+	if b.Value() != "b" {
+		t.Errorf("invalid value after mount want: b, got: %v", b.Value())
+	}
+
+	s.UpdateStore(map[string]interface{}{"title": "c"})
+	if b.Value() != "c" {
+		t.Errorf("invalid value after update want: c, got: %v", b.Value())
+	}
+}
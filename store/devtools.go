@@ -0,0 +1,240 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DevtoolsEvent event name for Data restoring by Devtools.SetState
+const DevtoolsEvent = "store/devtools"
+
+// DevtoolsEntry one logged event
+type DevtoolsEntry struct {
+	Event   string                 `json:"event"`
+	Values  []interface{}          `json:"values"`
+	Updates map[string]interface{} `json:"updates"`
+	Time    time.Time              `json:"time"`
+}
+
+// Devtools events log and state access for debugging tools.
+// In browser OnCreate exposes Store as window[Devtools.Global] (global object in workers) and connects Redux DevTools extension if it's installed.
+//
+// Use Devtools.OnCreate in Store.OnCreate and Devtools.AfterEmit in Store.AfterEmit
+type Devtools struct {
+	Name   string // instance name in Redux DevTools
+	Global string // global object property name, "__GAS_STORE__" if empty
+	Limit  int    // max logged events, 100 if 0
+
+	NoRedux bool // don't connect Redux DevTools
+
+	mu        sync.Mutex
+	log       []DevtoolsEntry
+	initial   map[string]interface{}
+	listeners map[int]func(e DevtoolsEntry)
+	nextID    int
+}
+
+// OnCreate remember initial state and connect browser devtools
+func (d *Devtools) OnCreate(s *Store) error {
+	if len(d.Global) == 0 {
+		d.Global = "__GAS_STORE__"
+	}
+
+	if d.Limit <= 0 {
+		d.Limit = 100
+	}
+
+	d.mu.Lock()
+	d.initial = make(map[string]interface{}, len(s.Data))
+	for name, value := range s.Data {
+		d.initial[name] = value
+	}
+	d.mu.Unlock()
+
+	return d.connect(s)
+}
+
+// AfterEmit log event and notify listeners after event is committed. Rolled back events aren't logged
+func (d *Devtools) AfterEmit(s *Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+	e := DevtoolsEntry{Event: eventName, Values: values, Updates: updatesMap, Time: s.clock().Now()}
+	s.onCommit(func() { d.record(e) })
+
+	return nil
+}
+
+// record log event and notify listeners
+func (d *Devtools) record(e DevtoolsEntry) {
+	d.mu.Lock()
+	d.log = append(d.log, e)
+	if len(d.log) > d.Limit {
+		d.log = d.log[len(d.log)-d.Limit:]
+	}
+
+	listeners := make([]func(e DevtoolsEntry), 0, len(d.listeners))
+	ids := make([]int, 0, len(d.listeners))
+	for id := range d.listeners {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		listeners = append(listeners, d.listeners[id])
+	}
+	d.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(e)
+	}
+}
+
+// Log return logged events from oldest to newest
+func (d *Devtools) Log() []DevtoolsEntry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make([]DevtoolsEntry, len(d.log))
+	copy(out, d.log)
+
+	return out
+}
+
+// Subscribe call fn for every new logged event. Listeners are called with locked events processing, they mustn't Emit synchronously
+func (d *Devtools) Subscribe(fn func(e DevtoolsEntry)) (unsubscribe func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.listeners == nil {
+		d.listeners = make(map[int]func(e DevtoolsEntry))
+	}
+
+	id := d.nextID
+	d.nextID++
+	d.listeners[id] = fn
+
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		delete(d.listeners, id)
+	}
+}
+
+// State return Store.Data as JSON. Fields which can't be encoded are replaced with their string representation
+func (d *Devtools) State(s *Store) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := make(map[string]json.RawMessage, len(s.Data))
+	for name, value := range s.Data {
+		state[name] = jsonOrString(value)
+	}
+
+	return json.Marshal(state)
+}
+
+// SetState replace Data fields by fields from JSON state. Fields are decoded like in Persist,
+// fields which can't be encoded to JSON are skipped. State changes aren't recorded to History
+func (d *Devtools) SetState(s *Store, raw []byte) error {
+	var state map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return err
+	}
+
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	updatesMap := make(map[string]interface{})
+	for name, value := range state {
+		s.mu.RLock()
+		current, ok := s.Data[name]
+		typ := s.fieldType(name)
+		s.mu.RUnlock()
+
+		if !ok {
+			continue
+		}
+
+		if _, err := json.Marshal(current); err != nil {
+			continue
+		}
+
+		decoded, err := decodeField(string(value), typ)
+		if err != nil {
+			return &FieldError{Event: DevtoolsEvent, Field: name, Err: err}
+		}
+
+		updatesMap[name] = decoded
+	}
+
+	return s.replay(DevtoolsEvent, updatesMap)
+}
+
+// Reset restore state Store had on create
+func (d *Devtools) Reset(s *Store) error {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	d.mu.Lock()
+	updatesMap := make(map[string]interface{}, len(d.initial))
+	for name, value := range d.initial {
+		updatesMap[name] = value
+	}
+	d.mu.Unlock()
+
+	return s.replay(DevtoolsEvent, updatesMap)
+}
+
+// replay commit updatesMap without recording it to History. emitMu must be locked
+func (s *Store) replay(eventName string, updatesMap map[string]interface{}) error {
+	s.replaying = true
+	defer func() { s.replaying = false }()
+
+	return s.commit(eventName, updatesMap, nil)
+}
+
+// JSON return entry as JSON. Values which can't be encoded are replaced with their string representation
+func (e DevtoolsEntry) JSON() []byte {
+	values := make([]json.RawMessage, len(e.Values))
+	for i, value := range e.Values {
+		values[i] = jsonOrString(value)
+	}
+
+	updates := make(map[string]json.RawMessage, len(e.Updates))
+	for key, value := range e.Updates {
+		updates[key] = jsonOrString(value)
+	}
+
+	raw, _ := json.Marshal(struct {
+		Event   string                     `json:"event"`
+		Values  []json.RawMessage          `json:"values"`
+		Updates map[string]json.RawMessage `json:"updates"`
+		Time    time.Time                  `json:"time"`
+	}{e.Event, values, updates, e.Time})
+
+	return raw
+}
+
+// HandlerNames return sorted names of all handlers
+func (s *Store) HandlerNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.Handlers))
+	for name := range s.Handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func jsonOrString(value interface{}) json.RawMessage {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		raw, _ = json.Marshal(fmt.Sprintf("%v", value))
+	}
+
+	return raw
+}
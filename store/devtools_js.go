@@ -0,0 +1,223 @@
+//go:build js && wasm
+// +build js,wasm
+
+package store
+
+import (
+	"encoding/json"
+	"syscall/js"
+)
+
+// connect expose Store on global object (window in browser) and connect Redux DevTools
+func (d *Devtools) connect(s *Store) error {
+	global := js.Global()
+
+	obj := js.Global().Get("Object").New()
+	obj.Set("getState", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		state, err := d.State(s)
+		if err != nil {
+			consoleError(err)
+			return nil
+		}
+
+		return parseJSON(state)
+	}))
+	obj.Set("setState", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 0 {
+			return nil
+		}
+
+		raw := js.Global().Get("JSON").Call("stringify", args[0]).String()
+		go func() {
+			if err := d.SetState(s, []byte(raw)); err != nil {
+				consoleError(err)
+			}
+		}()
+
+		return nil
+	}))
+	obj.Set("reset", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		go func() {
+			if err := d.Reset(s); err != nil {
+				consoleError(err)
+			}
+		}()
+
+		return nil
+	}))
+	obj.Set("emit", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 0 {
+			return nil
+		}
+
+		name := args[0].String()
+		values := make([]interface{}, len(args)-1)
+		for i, arg := range args[1:] {
+			values[i] = jsToGo(arg)
+		}
+
+		// handlers can block, js callbacks mustn't
+		go func() {
+			if err := s.Emit(name, values...); err != nil {
+				consoleError(err)
+			}
+		}()
+
+		return nil
+	}))
+	obj.Set("handlers", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		names := s.HandlerNames()
+
+		out := make([]interface{}, len(names))
+		for i, name := range names {
+			out[i] = name
+		}
+
+		return js.ValueOf(out)
+	}))
+	obj.Set("log", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		log := d.Log()
+
+		out := make([]json.RawMessage, len(log))
+		for i, e := range log {
+			out[i] = e.JSON()
+		}
+
+		raw, _ := json.Marshal(out)
+		return parseJSON(raw)
+	}))
+	obj.Set("subscribe", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 0 || args[0].Type() != js.TypeFunction {
+			return nil
+		}
+
+		fn := args[0]
+		unsubscribe := d.Subscribe(func(e DevtoolsEntry) {
+			fn.Invoke(parseJSON(e.JSON()))
+		})
+
+		var unsubscribeFunc js.Func
+		unsubscribeFunc = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			unsubscribe()
+			unsubscribeFunc.Release()
+			return nil
+		})
+
+		return unsubscribeFunc
+	}))
+
+	global.Set(d.Global, obj)
+
+	if !d.NoRedux {
+		d.connectRedux(s, global)
+	}
+
+	return nil
+}
+
+// connectRedux send events to Redux DevTools extension and handle its time travel messages
+func (d *Devtools) connectRedux(s *Store, global js.Value) {
+	ext := global.Get("__REDUX_DEVTOOLS_EXTENSION__")
+	if !ext.Truthy() {
+		return
+	}
+
+	devtools := ext.Call("connect", map[string]interface{}{"name": d.Name})
+
+	initState := func() {
+		state, err := d.State(s)
+		if err != nil {
+			consoleError(err)
+			return
+		}
+
+		devtools.Call("init", parseJSON(state))
+	}
+	initState()
+
+	d.Subscribe(func(e DevtoolsEntry) {
+		if e.Event == DevtoolsEvent {
+			return
+		}
+
+		state, err := d.State(s)
+		if err != nil {
+			consoleError(err)
+			return
+		}
+
+		action := parseJSON(e.JSON())
+		action.Set("type", e.Event)
+		devtools.Call("send", action, parseJSON(state))
+	})
+
+	devtools.Call("subscribe", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		msg := args[0]
+		if msg.Get("type").String() != "DISPATCH" {
+			return nil
+		}
+
+		switch msg.Get("payload").Get("type").String() {
+		case "JUMP_TO_STATE", "JUMP_TO_ACTION":
+			state := msg.Get("state").String()
+			go func() {
+				if err := d.SetState(s, []byte(state)); err != nil {
+					consoleError(err)
+				}
+			}()
+		case "ROLLBACK":
+			state := msg.Get("state").String()
+			go func() {
+				if err := d.SetState(s, []byte(state)); err != nil {
+					consoleError(err)
+					return
+				}
+
+				initState()
+			}()
+		case "RESET":
+			go func() {
+				if err := d.Reset(s); err != nil {
+					consoleError(err)
+					return
+				}
+
+				initState()
+			}()
+		case "COMMIT":
+			go initState()
+		}
+
+		return nil
+	}))
+}
+
+func parseJSON(raw []byte) js.Value {
+	return js.Global().Get("JSON").Call("parse", string(raw))
+}
+
+func consoleError(err error) {
+	js.Global().Get("console").Call("error", "store: "+err.Error())
+}
+
+// jsToGo convert js value to string, float64, bool, nil or value decoded from its JSON
+func jsToGo(value js.Value) interface{} {
+	switch value.Type() {
+	case js.TypeString:
+		return value.String()
+	case js.TypeNumber:
+		return value.Float()
+	case js.TypeBoolean:
+		return value.Bool()
+	case js.TypeUndefined, js.TypeNull:
+		return nil
+	}
+
+	var out interface{}
+	raw := js.Global().Get("JSON").Call("stringify", value).String()
+	if err := json.Unmarshal([]byte(raw), &out); err != nil {
+		return raw
+	}
+
+	return out
+}
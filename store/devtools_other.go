@@ -0,0 +1,9 @@
+//go:build !js || !wasm
+// +build !js !wasm
+
+package store
+
+// connect do nothing outside browser
+func (d *Devtools) connect(s *Store) error {
+	return nil
+}
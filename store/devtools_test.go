@@ -0,0 +1,138 @@
+package store
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDevtools(t *testing.T) {
+	d := &Devtools{Limit: 2}
+
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"counter": 0,
+			"user":    persistUser{Name: "guest"},
+			"format":  func() {},
+		},
+		Handlers: map[string]Handler{
+			"inc": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": s.Get("counter").(int) + values[0].(int)}, nil
+			},
+		},
+		OnCreate:  []OnCreateHook{d.OnCreate},
+		AfterEmit: []AfterEmitHook{d.AfterEmit},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	var events []string
+	unsubscribe := d.Subscribe(func(e DevtoolsEntry) {
+		events = append(events, e.Event)
+	})
+
+	for i := 1; i <= 3; i++ {
+		s.Emit("inc", i)
+	}
+	unsubscribe()
+	s.Emit("inc", 4)
+
+	if len(events) != 3 {
+		t.Errorf("invalid subscriber events: %v", events)
+	}
+
+	log := d.Log()
+	if len(log) != 2 || log[1].Values[0] != 4 {
+		t.Errorf("invalid log: %v", log)
+	}
+
+	if raw := string(log[1].JSON()); !strings.Contains(raw, `"updates":{"counter":10}`) {
+		t.Errorf("invalid entry JSON: %s", raw)
+	}
+
+	state, err := d.State(s)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if !strings.Contains(string(state), `"counter":10`) || !strings.Contains(string(state), `"format":"0x`) {
+		t.Errorf("invalid state: %s", state)
+	}
+
+	if err := d.SetState(s, []byte(`{"counter":3,"user":{"Name":"bob"}}`)); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	if s.Get("counter") != 3 || s.Get("user").(persistUser).Name != "bob" {
+		t.Errorf("state wasn't set: %v", s.Data)
+	}
+
+	if err := d.Reset(s); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	if s.Get("counter") != 0 {
+		t.Errorf("state wasn't reset: %v", s.Get("counter"))
+	}
+
+	if names := strings.Join(s.HandlerNames(), ","); names != "inc" {
+		t.Errorf("invalid handler names: %s", names)
+	}
+}
+
+// fixedClock Clock with stopped time
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func (c fixedClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func TestDevtoolsRollback(t *testing.T) {
+	d := &Devtools{NoRedux: true}
+	now := time.Unix(100, 0)
+
+	s, err := New(&Store{
+		Data: map[string]interface{}{"counter": 0},
+		Handlers: map[string]Handler{
+			"set": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": values[0]}, nil
+			},
+		},
+		Clock:    fixedClock{now: now},
+		OnCreate: []OnCreateHook{d.OnCreate},
+		AfterEmit: []AfterEmitHook{
+			d.AfterEmit,
+			func(s *Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+				if updatesMap["counter"] == 1 {
+					return errors.New("invalid counter")
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Emit("set", 1); err == nil {
+		t.Error("expected error")
+	}
+
+	s.Emit("set", 2)
+
+	log := d.Log()
+	if len(log) != 1 || log[0].Values[0] != 2 || !log[0].Time.Equal(now) {
+		t.Errorf("invalid log after rollback: %v", log)
+	}
+}
@@ -8,12 +8,18 @@ func NewHandlers() Handlers {
 	return make(map[string]Handler)
 }
 
-// Add add one handler to handlers
-func (h Handlers) Add(name string, handler Handler) {
+// Add add one handler to handlers. Policies limit how often handler runs:
+//
+// h.Add("search", searchHandler, store.Debounce(300*time.Millisecond), store.Dedupe())
+func (h Handlers) Add(name string, handler Handler, policies ...Policy) {
 	if handler == nil {
 		return
 	}
 
+	if len(policies) != 0 {
+		handler = withPolicies(handler, policies)
+	}
+
 	h[name] = handler
 }
 
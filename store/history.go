@@ -0,0 +1,180 @@
+package store
+
+import (
+	"errors"
+	"sync"
+)
+
+const (
+	// UndoEvent event name for Data restoring by Store.Undo
+	UndoEvent = "store/undo"
+	// RedoEvent event name for Data restoring by Store.Redo
+	RedoEvent = "store/redo"
+	// JumpEvent event name for Data restoring by Store.JumpTo
+	JumpEvent = "store/jump"
+)
+
+var (
+	// ErrHistoryDisabled if Store.History is nil
+	ErrHistoryDisabled = errors.New("history is disabled")
+	// ErrHistoryIndex if there is no entry for undo, redo or jump
+	ErrHistoryIndex = errors.New("invalid history index")
+)
+
+// HistoryEntry one recorded event
+type HistoryEntry struct {
+	Event   string
+	Values  []interface{}
+	Updates map[string]interface{}
+
+	Prev, Next map[string]interface{} // changed Data fields before and after event
+}
+
+// History bounded events history. Handlers must return new values instead of changing old ones,
+// otherwise previous values will be lost
+type History struct {
+	mu sync.Mutex
+
+	entries []HistoryEntry // ring buffer
+	start   int
+	length  int
+	cursor  int // count of applied entries
+}
+
+// NewHistory create history keeping last limit events
+func NewHistory(limit int) *History {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	return &History{entries: make([]HistoryEntry, limit)}
+}
+
+// Len return count of recorded entries
+func (h *History) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.length
+}
+
+// Cursor return count of applied entries. Entries after cursor can be redone
+func (h *History) Cursor() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.cursor
+}
+
+// Entries return all recorded entries from oldest to newest
+func (h *History) Entries() []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	out := make([]HistoryEntry, h.length)
+	for i := range out {
+		out[i] = h.get(i)
+	}
+
+	return out
+}
+
+func (h *History) get(i int) HistoryEntry {
+	return h.entries[(h.start+i)%len(h.entries)]
+}
+
+func (h *History) record(entry HistoryEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// new event drops undone entries
+	h.length = h.cursor
+
+	if h.length == len(h.entries) {
+		h.start = (h.start + 1) % len(h.entries)
+		h.length--
+	}
+
+	h.entries[(h.start+h.length)%len(h.entries)] = entry
+	h.length++
+	h.cursor = h.length
+}
+
+// restoring return Data fields for moving cursor to index
+func (h *History) restoring(index int) (map[string]interface{}, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if index < 0 || index > h.length || index == h.cursor {
+		return nil, ErrHistoryIndex
+	}
+
+	fields := make(map[string]interface{})
+	if index < h.cursor {
+		for i := h.cursor - 1; i >= index; i-- {
+			for key, value := range h.get(i).Prev {
+				fields[key] = value
+			}
+		}
+	} else {
+		for i := h.cursor; i < index; i++ {
+			for key, value := range h.get(i).Next {
+				fields[key] = value
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// Undo restore Data to state before last event
+func (s *Store) Undo() error {
+	if s.History == nil {
+		return ErrHistoryDisabled
+	}
+
+	return s.jump(UndoEvent, func(cursor int) int { return cursor - 1 })
+}
+
+// Redo apply last undone event
+func (s *Store) Redo() error {
+	if s.History == nil {
+		return ErrHistoryDisabled
+	}
+
+	return s.jump(RedoEvent, func(cursor int) int { return cursor + 1 })
+}
+
+// JumpTo restore Data to state after index events from History.Entries. JumpTo(0) restores Data before first entry
+func (s *Store) JumpTo(index int) error {
+	if s.History == nil {
+		return ErrHistoryDisabled
+	}
+
+	return s.jump(JumpEvent, func(int) int { return index })
+}
+
+// jump restore Data to state after target(cursor) events. Target is computed with emitMu locked,
+// so cursor can't be changed by concurrent events
+func (s *Store) jump(eventName string, target func(cursor int) int) error {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	index := target(s.History.Cursor())
+
+	fields, err := s.History.restoring(index)
+	if err != nil {
+		return err
+	}
+
+	err = s.replay(eventName, fields)
+	if err != nil {
+		return err
+	}
+
+	s.History.mu.Lock()
+	s.History.cursor = index
+	s.History.mu.Unlock()
+
+	return nil
+}
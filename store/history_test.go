@@ -0,0 +1,129 @@
+package store
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"counter": 0,
+			"title":   "",
+		},
+		Handlers: map[string]Handler{
+			"inc": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": s.Get("counter").(int) + 1}, nil
+			},
+			"rename": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"title": values[0]}, nil
+			},
+		},
+		History: NewHistory(3),
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Undo(); err != ErrHistoryIndex {
+		t.Errorf("invalid error for empty history: %v", err)
+	}
+
+	emit := func(event string, values ...interface{}) {
+		if err := s.Emit(event, values...); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+	}
+
+	check := func(step string, counter int, title string) {
+		if s.Get("counter") != counter || s.Get("title") != title {
+			t.Errorf("%s: invalid data want: %d '%s', got: %v", step, counter, title, s.Data)
+		}
+	}
+
+	emit("inc")
+	emit("rename", "first")
+	emit("inc")
+	emit("inc") // first entry will be dropped
+
+	if s.History.Len() != 3 {
+		t.Errorf("invalid history length want: 3, got: %d", s.History.Len())
+	}
+
+	if err := s.Undo(); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	check("undo", 2, "first")
+
+	if err := s.JumpTo(0); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	check("jump to 0", 1, "")
+
+	if err := s.Redo(); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	check("redo", 1, "first")
+
+	if err := s.JumpTo(3); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	check("jump to 3", 3, "first")
+
+	if err := s.Redo(); err != ErrHistoryIndex {
+		t.Errorf("invalid error for redo without undone entries: %v", err)
+	}
+
+	// new event drops undone entries
+	s.Undo()
+	s.Undo()
+	emit("rename", "second")
+	if s.History.Len() != 2 || s.History.Cursor() != 2 {
+		t.Errorf("undone entries weren't dropped: len %d, cursor %d", s.History.Len(), s.History.Cursor())
+	}
+	check("new event", 1, "second")
+}
+
+func TestHistoryConcurrentUndo(t *testing.T) {
+	s, err := New(&Store{
+		Data: map[string]interface{}{"counter": 0},
+		Handlers: map[string]Handler{
+			"inc": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": s.Get("counter").(int) + 1}, nil
+			},
+		},
+		History: NewHistory(1000),
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	const goroutines, steps = 8, 50
+
+	// every Undo follows its own Emit, so each Undo must remove exactly one event
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < steps; j++ {
+				if err := s.Emit("inc"); err != nil {
+					t.Errorf("unexpected error: %s", err.Error())
+					return
+				}
+
+				if err := s.Undo(); err != nil {
+					t.Errorf("unexpected error: %s", err.Error())
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Get("counter") != 0 || s.History.Cursor() != 0 {
+		t.Errorf("invalid data after undo want: 0, got: %v, cursor: %d", s.Get("counter"), s.History.Cursor())
+	}
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Event event going to be processed
+type Event struct {
+	Name   string
+	Values []interface{}
+
+	busy   bool // the same event is processing in other goroutine
+	replay bool // event is re-applied by Transaction.Revert
+}
+
+// Interceptor called before event handler lookup. Interceptor can change event values,
+// redirect event to another handler by changing Event.Name or cancel event by returning error.
+// Interceptors are called once per Emit, redirected event isn't intercepted again
+type Interceptor func(s *Store, e *Event) error
+
+// CancelError returned by Emit if event was canceled by Interceptor or BeforeEmitHook
+type CancelError struct {
+	Event string
+	Err   error
+}
+
+func (e *CancelError) Error() string {
+	return fmt.Sprintf("event '%s' was canceled: %s", e.Event, e.Err.Error())
+}
+
+// match return true if middleware must be called for event
+func (mw MiddleWare) match(eventName string) bool {
+	if mw.Regexp != nil {
+		return mw.Regexp.MatchString(eventName)
+	}
+
+	if len(mw.Pattern) != 0 {
+		ok, _ := path.Match(mw.Pattern, eventName)
+		return ok
+	}
+
+	return strings.HasPrefix(eventName, mw.Prefix)
+}
+
+// String return middleware matching rule
+func (mw MiddleWare) String() string {
+	switch {
+	case mw.Regexp != nil:
+		return "regexp '" + mw.Regexp.String() + "'"
+	case len(mw.Pattern) != 0:
+		return "pattern '" + mw.Pattern + "'"
+	default:
+		return "prefix '" + mw.Prefix + "'"
+	}
+}
+
+// sortMiddleWares sort middlewares by priority keeping declaration order for equal priorities
+func sortMiddleWares(middlewares []MiddleWare) error {
+	for _, mw := range middlewares {
+		if len(mw.Pattern) == 0 {
+			continue
+		}
+
+		if _, err := path.Match(mw.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid middleware pattern '%s': %s", mw.Pattern, err.Error())
+		}
+	}
+
+	sort.SliceStable(middlewares, func(i, j int) bool {
+		return middlewares[i].Priority > middlewares[j].Priority
+	})
+
+	return nil
+}
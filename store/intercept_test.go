@@ -0,0 +1,105 @@
+package store
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestInterceptors(t *testing.T) {
+	var calls []string
+
+	middleware := func(name string) func(s *Store, values []interface{}) error {
+		return func(s *Store, values []interface{}) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"title": "",
+		},
+		Handlers: map[string]Handler{
+			"setTitle": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"title": values[0]}, nil
+			},
+			"setTitleV2": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"title": "v2:" + values[0].(string)}, nil
+			},
+			"user/logout": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return nil, nil
+			},
+		},
+		Interceptors: []Interceptor{
+			func(s *Store, e *Event) error {
+				if len(e.Values) == 1 {
+					e.Values = []interface{}{strings.TrimSpace(e.Values[0].(string))}
+				}
+				return nil
+			},
+			func(s *Store, e *Event) error {
+				if e.Name == "setTitle" && strings.HasPrefix(e.Values[0].(string), "!") {
+					e.Name = "setTitleV2"
+				}
+				return nil
+			},
+		},
+		BeforeEmit: []BeforeEmitHook{
+			func(s *Store, eventName string, values []interface{}) error {
+				if len(values) == 1 && values[0] == "" {
+					return errors.New("empty title")
+				}
+				return nil
+			},
+		},
+		MiddleWares: []MiddleWare{
+			{Prefix: "set", Hook: middleware("prefix")},
+			{Pattern: "user/*", Hook: middleware("pattern")},
+			{Regexp: regexp.MustCompile(`V\d$`), Hook: middleware("regexp"), Priority: 10},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Emit("setTitle", "  hello "); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	if s.Get("title") != "hello" {
+		t.Errorf("values weren't transformed: %v", s.Get("title"))
+	}
+
+	if err := s.Emit("setTitle", "!world"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	if s.Get("title") != "v2:!world" {
+		t.Errorf("event wasn't redirected: %v", s.Get("title"))
+	}
+
+	err = s.Emit("setTitle", "   ")
+	cancelErr, ok := err.(*CancelError)
+	if !ok || cancelErr.Event != "setTitle" {
+		t.Errorf("invalid error for canceled event: %v", err)
+	}
+
+	if err := s.Emit("user/logout"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	want := []string{"prefix", "regexp", "prefix", "pattern"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("invalid middlewares calls want: %v, got: %v", want, calls)
+	}
+
+	if _, err := New(&Store{
+		Data:        map[string]interface{}{},
+		MiddleWares: []MiddleWare{{Pattern: "[", Hook: middleware("invalid")}},
+	}); err == nil {
+		t.Error("expected error for invalid middleware pattern")
+	}
+}
@@ -0,0 +1,94 @@
+// Package fake provides fake gas components for store and storetest tests
+package fake
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gascore/gas"
+)
+
+// Component fake store subscriber rendered by empty render core.
+// Register Component.C in store before Mount
+type Component struct {
+	C *gas.Component
+
+	render func() []interface{}
+
+	mu      sync.Mutex
+	renders int
+}
+
+// NewComponent create fake component. render returns component childes, it can be nil
+func NewComponent(render func() []interface{}) *Component {
+	c := &Component{render: render}
+	c.C = &gas.C{Root: c, RC: gas.GetEmptyRenderCore()}
+
+	return c
+}
+
+// Render render component childes
+func (c *Component) Render() *gas.Element {
+	c.mu.Lock()
+	c.renders++
+	c.mu.Unlock()
+
+	var childes []interface{}
+	if c.render != nil {
+		childes = c.render()
+	}
+
+	return gas.NE(&gas.E{}, childes...)
+}
+
+// Renders return count of component renders
+func (c *Component) Renders() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.renders
+}
+
+// Element return last rendered element
+func (c *Component) Element() *gas.Element {
+	return c.C.Element
+}
+
+// Mount render component as root and call Created hook as gas does
+func (c *Component) Mount() error {
+	return c.MountIn(nil)
+}
+
+// MountIn render component as child of parent element and call Created hook.
+// Use Component.Element() or storetest.Wrap result as parent to build components tree
+func (c *Component) MountIn(parent *gas.Element) error {
+	err := c.C.UpdateWithError()
+	if err != nil {
+		return err
+	}
+
+	c.C.Element.Parent = parent
+
+	if c.C.Hooks.Created != nil {
+		return c.C.Hooks.Created()
+	}
+
+	return nil
+}
+
+// Destroy call BeforeDestroy hook as gas does
+func (c *Component) Destroy() error {
+	return gas.CallBeforeDestroy(c.C.Element)
+}
+
+// AssertRenders check count of component renders
+func (c *Component) AssertRenders(t testing.TB, renders int) bool {
+	t.Helper()
+
+	if got := c.Renders(); got != renders {
+		t.Errorf("invalid renders count want: %d, got: %d", renders, got)
+		return false
+	}
+
+	return true
+}
@@ -1,49 +1,28 @@
+//go:build js && wasm
+// +build js,wasm
+
 package store
 
-import (
-	"errors"
-	webStore "github.com/gascore/std/localStorage"
-)
+var lsSync = &Persist{Storage: LocalStorage(), Prefix: "data."}
 
-// LSSyncOnCreate syncronize data from localStorage and store data. Use in OnCreate hook 
-func LSSyncOnCreate(s *Store) error {
-	var localStorage = webStore.NewDataStore(webStore.JSONEncoding)
+// lsSyncLegacyKey localStorage key LSSync stored all Data by before Persist
+const lsSyncLegacyKey = "data"
 
-	var dataRaw interface{}
-	err := localStorage.Get("data", &dataRaw)
-	if err != nil && err != webStore.ErrNilValue {
+// LSSyncOnCreate syncronize data from localStorage and store data. Use in OnCreate hook.
+// Data stored by old LSSync in single "data" key is moved to Persist keys
+//
+// Deprecated: use Persist.OnCreate
+func LSSyncOnCreate(s *Store) error {
+	if err := lsSync.importLegacy(lsSyncLegacyKey); err != nil {
 		return err
 	}
 
-	if dataRaw == nil {
-		return nil
-	}
-
-	data, ok := dataRaw.(map[string]interface{})
-	if !ok {
-		return errors.New("invalid data type")
-	}
-
-	// merge data from localStorage and default Data
-	for key, value := range s.Data {
-		if _, ok := data[key]; !ok {
-			data[key] = value
-		}
-	}
-
-	s.Data = data
-	
-	return nil
+	return lsSync.OnCreate(s)
 }
 
 // LSSyncAfterEmit syncronize data from localStorage and store data. Use in AfterEmit hook
+//
+// Deprecated: use Persist.AfterEmit
 func LSSyncAfterEmit(s *Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
-	var localStorage = webStore.NewDataStore(webStore.JSONEncoding)
-
-	err := localStorage.Set("data", s.Data)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
\ No newline at end of file
+	return lsSync.AfterEmit(s, eventName, updatesMap, values)
+}
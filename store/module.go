@@ -0,0 +1,232 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ModuleSeparator separates module namespace and event name: "cart/add"
+const ModuleSeparator = "/"
+
+// Module namespaced part of Store.
+// Module Data is mounted to Store.Data[namespace] ("cart.items"), module handlers are mounted as "namespace/event" ("cart/add").
+// Module handlers return updatesMap with keys relative to module Data.
+// Module middlewares and hooks are called only for module events with event names without namespace
+type Module struct {
+	Data     map[string]interface{}
+	Handlers map[string]Handler
+
+	MiddleWares []MiddleWare
+
+	OnCreate   []OnCreateHook
+	BeforeEmit []BeforeEmitHook
+	AfterEmit  []AfterEmitHook
+
+	namespace string
+}
+
+// RegisterModule mount module to running store
+func (s *Store) RegisterModule(namespace string, module *Module) error {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	err := s.mount(namespace, module)
+	if err != nil {
+		return err
+	}
+
+	return s.moduleChanged(namespace)
+}
+
+// UnregisterModule remove module Data and handlers from store
+func (s *Store) UnregisterModule(namespace string) error {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	if !s.unmount(namespace) {
+		return fmt.Errorf("undefined module: %s", namespace)
+	}
+
+	return s.moduleChanged(namespace)
+}
+
+// moduleChanged update computed values, subscribers and watchers depending on module Data like publish does
+func (s *Store) moduleChanged(namespace string) error {
+	keys := append([]string{namespace}, s.invalidateComputed([]string{namespace})...)
+
+	err := s.update(keys)
+	s.runWatchers(keys)
+
+	return err
+}
+
+// unmount remove module Data and handlers from store. Return false if module isn't mounted
+func (s *Store) unmount(namespace string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	module, ok := s.modules[namespace]
+	if !ok {
+		return false
+	}
+
+	for name := range module.Handlers {
+		delete(s.Handlers, namespace+ModuleSeparator+name)
+	}
+
+	delete(s.Data, namespace)
+	if s.Schema != nil {
+		delete(s.Schema, namespace)
+	}
+
+	delete(s.modules, namespace)
+	return true
+}
+
+// mount add module Data and handlers to store
+func (s *Store) mount(namespace string, module *Module) error {
+	if module == nil {
+		return fmt.Errorf("module '%s' is nil", namespace)
+	}
+
+	if len(namespace) == 0 || strings.ContainsAny(namespace, ModuleSeparator+".[]") {
+		return fmt.Errorf("invalid module namespace: '%s'", namespace)
+	}
+
+	err := sortMiddleWares(module.MiddleWares)
+	if err != nil {
+		return fmt.Errorf("module '%s': %s", namespace, err.Error())
+	}
+
+	data := make(map[string]interface{}, len(module.Data))
+	for key, value := range module.Data {
+		data[key] = value
+	}
+
+	s.mu.Lock()
+	if _, ok := s.modules[namespace]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("module '%s' already registered", namespace)
+	}
+
+	if _, ok := s.Data[namespace]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("module '%s': Data has field with same name", namespace)
+	}
+
+	for name := range module.Handlers {
+		if _, ok := s.Handlers[namespace+ModuleSeparator+name]; ok {
+			s.mu.Unlock()
+			return fmt.Errorf("module '%s': handler '%s' already exists", namespace, name)
+		}
+	}
+
+	if s.Data == nil {
+		s.Data = make(map[string]interface{})
+	}
+
+	if s.Handlers == nil {
+		s.Handlers = make(map[string]Handler)
+	}
+
+	if s.modules == nil {
+		s.modules = make(map[string]*Module)
+	}
+
+	s.Data[namespace] = data
+	if s.Schema != nil {
+		s.Schema[namespace] = Field{Type: reflect.TypeOf(data)}
+	}
+
+	for name, handler := range module.Handlers {
+		s.Handlers[namespace+ModuleSeparator+name] = handler
+	}
+
+	module.namespace = namespace
+	s.modules[namespace] = module
+	s.mu.Unlock()
+
+	for _, create := range module.OnCreate {
+		err := create(s)
+		if err != nil {
+			s.unmount(namespace)
+			return fmt.Errorf("module '%s': %s", namespace, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Namespace return namespace module is mounted to
+func (module *Module) Namespace() string {
+	return module.namespace
+}
+
+// Get proxy for Store.Get with query relative to module Data
+func (module *Module) Get(s *Store, query string) interface{} {
+	if strings.HasPrefix(query, "[") {
+		return s.Get(module.namespace + query)
+	}
+
+	return s.Get(module.namespace + "." + query)
+}
+
+// moduleOf return module and module event name for event. s.mu must be locked
+func (s *Store) moduleOf(eventName string) (*Module, string) {
+	index := strings.Index(eventName, ModuleSeparator)
+	if index == -1 {
+		return nil, ""
+	}
+
+	module, ok := s.modules[eventName[:index]]
+	if !ok {
+		return nil, ""
+	}
+
+	return module, eventName[index+len(ModuleSeparator):]
+}
+
+// checkModuleField check that path is declared field of module Data and field keeps its type like Store.Data fields
+func checkModuleField(root interface{}, path []string, value interface{}) error {
+	data, ok := root.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	old, ok := data[path[0]]
+	if !ok {
+		return fmt.Errorf("undefined field in module Data: %s", path[0])
+	}
+
+	if len(path) == 1 && old != nil && value != nil && reflect.TypeOf(value) != reflect.TypeOf(old) {
+		return fmt.Errorf("uncompared fields: %T and %T", value, old)
+	}
+
+	return nil
+}
+
+// toStoreKeys prefix updatesMap keys with module namespace
+func (module *Module) toStoreKeys(updatesMap map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(updatesMap))
+	for key, value := range updatesMap {
+		if strings.HasPrefix(key, "[") {
+			out[module.namespace+key] = value
+			continue
+		}
+
+		out[module.namespace+"."+key] = value
+	}
+
+	return out
+}
+
+// toModuleKeys remove module namespace from updatesMap keys
+func (module *Module) toModuleKeys(updatesMap map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(updatesMap))
+	for key, value := range updatesMap {
+		out[strings.TrimPrefix(strings.TrimPrefix(key, module.namespace), ".")] = value
+	}
+
+	return out
+}
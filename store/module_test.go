@@ -0,0 +1,184 @@
+package store
+
+import (
+	"errors"
+	"testing"
+)
+
+func newCartModule(events *[]string) *Module {
+	var m *Module
+	m = &Module{
+		Data: map[string]interface{}{
+			"items": []string{},
+		},
+		Handlers: map[string]Handler{
+			"add": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				items := m.Get(s, "items").([]string)
+				return map[string]interface{}{
+					"items": append(append([]string{}, items...), values[0].(string)),
+				}, nil
+			},
+		},
+		MiddleWares: []MiddleWare{
+			{
+				Prefix: "add",
+				Hook: func(s *Store, values []interface{}) error {
+					*events = append(*events, "middleware")
+					return nil
+				},
+			},
+		},
+		AfterEmit: []AfterEmitHook{
+			func(s *Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+				if _, ok := updatesMap["items"]; ok {
+					*events = append(*events, "after:"+eventName)
+				}
+				return nil
+			},
+		},
+	}
+
+	return m
+}
+
+func TestModules(t *testing.T) {
+	var events []string
+
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"counter": 0,
+		},
+		Handlers: map[string]Handler{
+			"add": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": s.Get("counter").(int) + 1}, nil
+			},
+		},
+		Modules: map[string]*Module{
+			"cart": newCartModule(&events),
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Emit("cart/add", "apple"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Emit("add"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	items, ok := s.Get("cart.items").([]string)
+	if !ok || len(items) != 1 || items[0] != "apple" || s.Get("counter") != 1 {
+		t.Errorf("invalid data: %v", s.Data)
+	}
+
+	if len(events) != 2 || events[0] != "middleware" || events[1] != "after:add" {
+		t.Errorf("invalid module hooks calls: %v", events)
+	}
+
+	if err := s.UpdateStore(map[string]interface{}{"cart.items": 1}); err == nil {
+		t.Error("expected error for module field type changing")
+	}
+
+	if err := s.UpdateStore(map[string]interface{}{"cart.itmes": []string{}}); err == nil || s.Get("cart.itmes") != nil {
+		t.Error("expected error for undeclared module field")
+	}
+
+	// lazy modules
+	if err := s.RegisterModule("cart", newCartModule(&events)); err == nil {
+		t.Error("expected error for duplicate module")
+	}
+
+	if err := s.RegisterModule("wishlist", newCartModule(&events)); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Emit("wishlist/add", "pear"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if s.Get("wishlist.items[0]") != "pear" {
+		t.Errorf("invalid wishlist data: %v", s.Data)
+	}
+
+	if err := s.UnregisterModule("wishlist"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if s.Emit("wishlist/add", "pear") == nil || s.Get("wishlist") != nil {
+		t.Error("module wasn't unregistered")
+	}
+}
+
+func TestModuleOnCreateError(t *testing.T) {
+	s, err := New(&Store{Data: map[string]interface{}{}})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	failing := &Module{
+		Data: map[string]interface{}{"items": []string{}},
+		OnCreate: []OnCreateHook{func(s *Store) error {
+			return errors.New("can't load cart")
+		}},
+	}
+
+	if err := s.RegisterModule("cart", failing); err == nil {
+		t.Error("expected error from module OnCreate")
+	}
+
+	if s.Get("cart") != nil || s.Handlers["cart/add"] != nil {
+		t.Error("failed module wasn't unmounted")
+	}
+
+	var events []string
+	if err := s.RegisterModule("cart", newCartModule(&events)); err != nil {
+		t.Errorf("unexpected error after failed registration: %s", err.Error())
+	}
+}
+
+func TestUnregisterModuleComputed(t *testing.T) {
+	var events []string
+	s, err := New(&Store{
+		Data: map[string]interface{}{},
+		Computed: map[string]Computed{
+			"count": {Deps: []string{"cart.items"}, Get: func(s *Store) (interface{}, error) {
+				items, _ := s.Get("cart.items").([]string)
+				return len(items), nil
+			}},
+		},
+		Modules: map[string]*Module{"cart": newCartModule(&events)},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	s.Emit("cart/add", "apple")
+	if s.Get("count") != 1 {
+		t.Errorf("invalid count: %v", s.Get("count"))
+	}
+
+	var watched []interface{}
+	s.Watch("count", func(old, new interface{}) {
+		watched = append(watched, new)
+	})
+
+	if err := s.UnregisterModule("cart"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if s.Get("count") != 0 || len(watched) != 1 || watched[0] != 0 {
+		t.Errorf("computed value wasn't updated: %v, watched: %v", s.Get("count"), watched)
+	}
+}
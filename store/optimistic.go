@@ -0,0 +1,278 @@
+package store
+
+import (
+	"errors"
+)
+
+// RevertEvent event name for Data rebuilding by Transaction.Revert
+const RevertEvent = "store/revert"
+
+// ErrTransactionDone if transaction was already confirmed or reverted
+var ErrTransactionDone = errors.New("transaction is already done")
+
+const (
+	txPending = iota
+	txConfirmed
+	txReverted
+)
+
+// Transaction optimistic event waiting for confirmation
+type Transaction struct {
+	ID     int
+	Event  string
+	Values []interface{}
+
+	s     *Store
+	state int
+}
+
+// journalEntry event committed while optimistic transactions are pending
+type journalEntry struct {
+	tx         *Transaction // nil for regular events
+	event      string       // empty for UpdateStore
+	values     []interface{}
+	updatesMap map[string]interface{}
+	next       map[string]interface{} // changed Data fields after event, nil for not last events of batch
+}
+
+// EmitOptimistic runs event like Emit and return transaction for it.
+// Confirm transaction when backend accepts change or Revert it to remove event from Data.
+// Events emitted after optimistic one are re-applied on top of Data without reverted event,
+// changes made by UpdateStore are re-applied as is
+func (s *Store) EmitOptimistic(query string, values ...interface{}) (*Transaction, error) {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	e := &Event{Name: query, Values: values}
+	updatesMap, err := s.handle(e)
+	if err != nil {
+		return nil, err
+	}
+
+	s.txSeq++
+	tx := &Transaction{ID: s.txSeq, Event: e.Name, Values: e.Values, s: s}
+
+	if updatesMap == nil {
+		tx.state = txConfirmed // nothing to revert
+		return tx, nil
+	}
+
+	s.optimistic = tx
+	defer func() { s.optimistic = nil }()
+
+	err = s.commit(e.Name, updatesMap, e.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// Pending return optimistic transactions waiting for confirmation
+func (s *Store) Pending() []*Transaction {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	var out []*Transaction
+	for _, entry := range s.journal {
+		if entry.tx != nil && entry.tx.state == txPending {
+			out = append(out, entry.tx)
+		}
+	}
+
+	return out
+}
+
+// Pending return true if transaction isn't confirmed or reverted
+func (tx *Transaction) Pending() bool {
+	tx.s.emitMu.Lock()
+	defer tx.s.emitMu.Unlock()
+
+	return tx.state == txPending
+}
+
+// Confirm make transaction event permanent
+func (tx *Transaction) Confirm() error {
+	s := tx.s
+
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	if tx.state != txPending {
+		return ErrTransactionDone
+	}
+
+	tx.state = txConfirmed
+	s.compactJournal()
+
+	return nil
+}
+
+// Revert remove transaction event from Data. Events emitted after transaction are re-applied by their handlers
+// (without hooks and middlewares). If one of them fails, Data isn't changed and error is returned
+func (tx *Transaction) Revert() error {
+	s := tx.s
+
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	if tx.state != txPending {
+		return ErrTransactionDone
+	}
+
+	index := -1
+	for i, entry := range s.journal {
+		if entry.tx == tx {
+			index = i
+			break
+		}
+	}
+
+	if index == -1 {
+		return ErrTransactionDone
+	}
+
+	// Data before transaction
+	base := make(map[string]interface{}, len(s.checkpoint))
+	for name, value := range s.checkpoint {
+		base[name] = value
+	}
+
+	for _, entry := range s.journal[:index] {
+		for name, value := range entry.next {
+			base[name] = value
+		}
+	}
+
+	b := &Batch{s: s, prev: make(map[string]interface{})}
+
+	restoring := make(map[string]interface{})
+	for _, entry := range s.journal[index:] {
+		for name := range entry.next {
+			if value, ok := base[name]; ok {
+				restoring[name] = value
+			}
+		}
+	}
+
+	_, err := b.apply(RevertEvent, restoring)
+	if err != nil {
+		s.restore(b.keys, b.prev)
+		return err
+	}
+
+	rest := make([]journalEntry, 0, len(s.journal)-1)
+	rest = append(rest, s.journal[:index]...)
+
+	for _, entry := range s.journal[index+1:] {
+		updatesMap, err := s.rerun(entry)
+		entry.updatesMap, entry.next = updatesMap, nil
+		if err == nil && updatesMap != nil {
+			var prev map[string]interface{}
+			prev, err = b.apply(entry.event, updatesMap)
+			if err == nil {
+				entry.next = s.fields(prev)
+			}
+		}
+
+		if err != nil {
+			s.restore(b.keys, b.prev)
+			return err
+		}
+
+		rest = append(rest, entry)
+	}
+
+	if len(b.keys) != 0 {
+		err = s.publish([]emitted{{event: RevertEvent, updatesMap: s.fields(b.prev), values: []interface{}{tx.ID, tx.Event}}}, b.keys, b.prev)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.journal = rest
+	tx.state = txReverted
+	s.compactJournal()
+
+	return nil
+}
+
+// rerun return updatesMap of journal entry for current Data
+func (s *Store) rerun(entry journalEntry) (map[string]interface{}, error) {
+	s.mu.RLock()
+	handler := s.Handlers[entry.event]
+	module, _ := s.moduleOf(entry.event)
+	s.mu.RUnlock()
+
+	if len(entry.event) == 0 || handler == nil {
+		return entry.updatesMap, nil // not event or event without handler (undo, sync, etc.)
+	}
+
+	s.current = &Event{Name: entry.event, Values: entry.values, replay: true}
+	updatesMap, err := handler(s, entry.values...)
+	s.current = nil
+	if err != nil || updatesMap == nil {
+		return nil, err
+	}
+
+	if module != nil {
+		updatesMap = module.toStoreKeys(updatesMap)
+	}
+
+	return updatesMap, nil
+}
+
+// journalRecord add committed events to journal if there are pending transactions. emitMu must be locked
+func (s *Store) journalRecord(events []emitted, prev map[string]interface{}) {
+	if len(s.journal) == 0 && s.optimistic == nil {
+		return
+	}
+
+	for _, e := range events {
+		if e.event == RevertEvent {
+			return // revert updates journal by itself
+		}
+	}
+
+	next := s.fields(prev)
+
+	if s.checkpoint == nil {
+		s.checkpoint = s.Values()
+		for name, value := range prev {
+			s.checkpoint[name] = value
+		}
+	}
+
+	if len(events) == 0 { // UpdateStore
+		s.journal = append(s.journal, journalEntry{updatesMap: next, next: next})
+		return
+	}
+
+	for i, e := range events {
+		entry := journalEntry{tx: s.optimistic, event: e.event, values: e.values, updatesMap: e.updatesMap}
+		if i == len(events)-1 {
+			entry.next = next
+		}
+
+		s.journal = append(s.journal, entry)
+	}
+}
+
+// compactJournal move finished events from journal start to checkpoint. emitMu must be locked
+func (s *Store) compactJournal() {
+	for len(s.journal) != 0 {
+		entry := s.journal[0]
+		if entry.tx != nil && entry.tx.state == txPending {
+			return
+		}
+
+		for name, value := range entry.next {
+			s.checkpoint[name] = value
+		}
+
+		s.journal = s.journal[1:]
+	}
+
+	s.journal = nil
+	s.checkpoint = nil
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fakeBackend accept or reject requests by their order
+type fakeBackend struct {
+	requests []*Transaction
+}
+
+func (b *fakeBackend) send(tx *Transaction) {
+	b.requests = append(b.requests, tx)
+}
+
+func (b *fakeBackend) respond(i int, ok bool) error {
+	if ok {
+		return b.requests[i].Confirm()
+	}
+
+	return b.requests[i].Revert()
+}
+
+func newTodoStore() (*Store, error) {
+	return New(&Store{
+		Data: map[string]interface{}{
+			"todos": []string{},
+			"count": 0,
+		},
+		Handlers: map[string]Handler{
+			"add": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				todos := append([]string{}, s.Get("todos").([]string)...)
+				return map[string]interface{}{
+					"todos": append(todos, values[0].(string)),
+					"count": s.Get("count").(int) + 1,
+				}, nil
+			},
+			"double": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"count": s.Get("count").(int) * 2}, nil
+			},
+			"failIfEmpty": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				if len(s.Get("todos").([]string)) == 0 {
+					return nil, errors.New("empty todos")
+				}
+				return map[string]interface{}{"count": 100}, nil
+			},
+		},
+		History: NewHistory(10),
+	})
+}
+
+func TestOptimistic(t *testing.T) {
+	s, err := newTodoStore()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	backend := &fakeBackend{}
+	for _, todo := range []string{"a", "b", "c"} {
+		tx, err := s.EmitOptimistic("add", todo)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+
+		backend.send(tx)
+	}
+
+	s.Emit("double") // regular event after optimistic ones
+
+	if len(s.Pending()) != 3 || s.Get("count") != 6 {
+		t.Errorf("invalid state before responses: %v, %v", s.Pending(), s.Data)
+		return
+	}
+
+	data := []struct {
+		index int
+		ok    bool
+		todos []string
+		count int
+	}{
+		{index: 0, ok: true, todos: []string{"a", "b", "c"}, count: 6},
+		{index: 1, ok: false, todos: []string{"a", "c"}, count: 4}, // "double" is rebased: (1 + 1) * 2
+		{index: 2, ok: false, todos: []string{"a"}, count: 2},
+	}
+
+	for _, el := range data {
+		err := backend.respond(el.index, el.ok)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+
+		if !reflect.DeepEqual(s.Get("todos"), el.todos) || s.Get("count") != el.count {
+			t.Errorf("invalid state after response %d want: %v %d, got: %v %v", el.index, el.todos, el.count, s.Get("todos"), s.Get("count"))
+		}
+	}
+
+	if len(s.Pending()) != 0 || len(s.journal) != 0 || s.checkpoint != nil {
+		t.Errorf("journal wasn't cleared: %v, %v", s.journal, s.checkpoint)
+	}
+
+	if err := backend.respond(0, false); err != ErrTransactionDone {
+		t.Errorf("invalid error for finished transaction: %v", err)
+	}
+
+	if s.History.Len() == 0 || s.History.Entries()[s.History.Len()-1].Event != RevertEvent {
+		t.Error("revert wasn't recorded to history")
+	}
+}
+
+func TestOptimisticRebaseError(t *testing.T) {
+	s, err := newTodoStore()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	tx, err := s.EmitOptimistic("add", "a")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Emit("failIfEmpty"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := tx.Revert(); err == nil {
+		t.Error("expected error for failed rebase")
+	}
+
+	if !reflect.DeepEqual(s.Get("todos"), []string{"a"}) || s.Get("count") != 100 || !tx.Pending() {
+		t.Errorf("state was changed by failed revert: %v", s.Data)
+	}
+}
+
+func TestOptimisticConcurrentUpdate(t *testing.T) {
+	s, err := newTodoStore()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			s.UpdateStore(map[string]interface{}{"count": i})
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		tx, err := s.EmitOptimistic("add", "a")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			break
+		}
+
+		if i%2 == 0 {
+			err = tx.Revert()
+		} else {
+			err = tx.Confirm()
+		}
+
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			break
+		}
+	}
+	<-done
+
+	if len(s.Pending()) != 0 {
+		t.Errorf("invalid pending transactions: %v", s.Pending())
+	}
+}
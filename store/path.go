@@ -0,0 +1,323 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parsePath split query to path parts: "todos[3].done" => ["todos", "3", "done"]
+func parsePath(query string) ([]string, error) {
+	var parts []string
+	var part strings.Builder
+	inBrackets := false
+
+	flush := func() error {
+		if part.Len() == 0 {
+			return fmt.Errorf("invalid path: %s", query)
+		}
+
+		parts = append(parts, part.String())
+		part.Reset()
+		return nil
+	}
+
+	for i, r := range query {
+		switch {
+		case r == '.' && !inBrackets:
+			// "todos[3].done" - part was flushed by ']'
+			if i > 0 && query[i-1] == ']' {
+				continue
+			}
+
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case r == '[' && !inBrackets:
+			if i > 0 && query[i-1] != ']' {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+
+			inBrackets = true
+		case r == ']' && inBrackets:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+
+			inBrackets = false
+		case r == '[' || r == ']':
+			return nil, fmt.Errorf("invalid path: %s", query)
+		default:
+			if i > 0 && query[i-1] == ']' {
+				return nil, fmt.Errorf("invalid path: %s", query)
+			}
+
+			part.WriteRune(r)
+		}
+	}
+
+	if inBrackets {
+		return nil, fmt.Errorf("invalid path: %s", query)
+	}
+
+	if part.Len() != 0 {
+		parts = append(parts, part.String())
+	} else if len(query) == 0 || query[len(query)-1] != ']' {
+		return nil, fmt.Errorf("invalid path: %s", query)
+	}
+
+	return parts, nil
+}
+
+// isPath return true if query is nested path
+func isPath(query string) bool {
+	return strings.ContainsAny(query, ".[")
+}
+
+// getPath return value from root by path through maps, slices and exported struct fields
+func getPath(root interface{}, path []string) (interface{}, error) {
+	v := reflect.ValueOf(root)
+	for i, part := range path {
+		v = indirect(v)
+		if !v.IsValid() {
+			return nil, fmt.Errorf("nil value at: %s", strings.Join(path[:i], "."))
+		}
+
+		child, err := child(v, part)
+		if err != nil {
+			return nil, err
+		}
+
+		v = child
+	}
+
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	return v.Interface(), nil
+}
+
+// setPath return copy of root with value set by path. Root itself isn't changed
+func setPath(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if root == nil {
+		return nil, errors.New("can't set value in nil")
+	}
+
+	v, err := setValue(reflect.ValueOf(root), path, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Interface(), nil
+}
+
+func setValue(v reflect.Value, path []string, value interface{}) (reflect.Value, error) {
+	if len(path) == 0 {
+		return newValue(v.Type(), value)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Value{}, errors.New("can't set value in nil")
+		}
+
+		elem, err := setValue(v.Elem(), path, value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out := reflect.New(v.Type()).Elem()
+		out.Set(elem)
+		return out, nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Value{}, errors.New("can't set value in nil")
+		}
+
+		elem, err := setValue(v.Elem(), path, value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(elem)
+		return out, nil
+	case reflect.Map:
+		key, err := mapKey(v.Type().Key(), path[0])
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		old := v.MapIndex(key)
+		if !old.IsValid() {
+			if len(path) != 1 {
+				return reflect.Value{}, fmt.Errorf("undefined key: %s", path[0])
+			}
+
+			old = reflect.Zero(v.Type().Elem())
+		}
+
+		elem, err := setValue(old, path[1:], value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out := reflect.MakeMapWithSize(v.Type(), v.Len()+1)
+		for _, k := range v.MapKeys() {
+			out.SetMapIndex(k, v.MapIndex(k))
+		}
+		out.SetMapIndex(key, elem)
+		return out, nil
+	case reflect.Slice, reflect.Array:
+		index, err := sliceIndex(v, path[0])
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		elem, err := setValue(v.Index(index), path[1:], value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		var out reflect.Value
+		if v.Kind() == reflect.Slice {
+			out = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			reflect.Copy(out, v)
+		} else {
+			out = reflect.New(v.Type()).Elem()
+			out.Set(v)
+		}
+		out.Index(index).Set(elem)
+		return out, nil
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+
+		field, err := structField(out, path[0])
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		elem, err := setValue(field, path[1:], value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		field.Set(elem)
+		return out, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("can't get '%s' from %s", path[0], v.Type().String())
+	}
+}
+
+// newValue create value with type t
+func newValue(t reflect.Type, value interface{}) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return out, nil
+		default:
+			return reflect.Value{}, fmt.Errorf("can't set nil to %s", t.String())
+		}
+	}
+
+	if !reflect.TypeOf(value).AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("uncompared types: %T and %s", value, t.String())
+	}
+
+	out.Set(reflect.ValueOf(value))
+	return out, nil
+}
+
+// indirect unwrap pointers and interfaces
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
+
+func child(v reflect.Value, part string) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Map:
+		key, err := mapKey(v.Type().Key(), part)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out := v.MapIndex(key)
+		if !out.IsValid() {
+			return reflect.Value{}, fmt.Errorf("undefined key: %s", part)
+		}
+
+		return out, nil
+	case reflect.Slice, reflect.Array:
+		index, err := sliceIndex(v, part)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return v.Index(index), nil
+	case reflect.Struct:
+		return structField(v, part)
+	default:
+		return reflect.Value{}, fmt.Errorf("can't get '%s' from %s", part, v.Type().String())
+	}
+}
+
+func mapKey(t reflect.Type, part string) (reflect.Value, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(part).Convert(t), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(part, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid map key: %s", part)
+		}
+
+		return reflect.ValueOf(i).Convert(t), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(part, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid map key: %s", part)
+		}
+
+		return reflect.ValueOf(i).Convert(t), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported map key type: %s", t.String())
+	}
+}
+
+func sliceIndex(v reflect.Value, part string) (int, error) {
+	index, err := strconv.Atoi(part)
+	if err != nil {
+		return 0, fmt.Errorf("invalid index: %s", part)
+	}
+
+	if index < 0 || index >= v.Len() {
+		return 0, fmt.Errorf("index out of range: %d", index)
+	}
+
+	return index, nil
+}
+
+func structField(v reflect.Value, part string) (reflect.Value, error) {
+	field, ok := v.Type().FieldByName(part)
+	if !ok || len(field.PkgPath) != 0 {
+		return reflect.Value{}, fmt.Errorf("undefined exported field: %s", part)
+	}
+
+	return v.FieldByIndex(field.Index), nil
+}
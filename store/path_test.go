@@ -0,0 +1,140 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+type pathProfile struct {
+	Name string
+	age  int
+}
+
+type pathUser struct {
+	Profile *pathProfile
+	Tags    []string
+}
+
+type pathTodo struct {
+	Title string
+	Done  bool
+}
+
+func TestParsePath(t *testing.T) {
+	data := []struct {
+		query string
+		path  []string
+	}{
+		{query: "user", path: []string{"user"}},
+		{query: "user.profile.name", path: []string{"user", "profile", "name"}},
+		{query: "todos[3].done", path: []string{"todos", "3", "done"}},
+		{query: "matrix[1][2]", path: []string{"matrix", "1", "2"}},
+		{query: "users[artem.k].age", path: []string{"users", "artem.k", "age"}},
+		{query: "", path: nil},
+		{query: "user.", path: nil},
+		{query: "user..name", path: nil},
+		{query: "todos[3", path: nil},
+		{query: "todos[]", path: nil},
+		{query: "todos[3]done", path: nil},
+	}
+
+	for _, el := range data {
+		path, err := parsePath(el.query)
+		if el.path == nil {
+			if err == nil {
+				t.Errorf("expected error for query: '%s', got: %v", el.query, path)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("unexpected error for query '%s': %s", el.query, err.Error())
+			continue
+		}
+
+		if !reflect.DeepEqual(path, el.path) {
+			t.Errorf("invalid path for query '%s' want: %v, got: %v", el.query, el.path, path)
+		}
+	}
+}
+
+func TestNestedPaths(t *testing.T) {
+	profile := &pathProfile{Name: "Artem"}
+	settings := map[string]interface{}{"theme": "dark"}
+	todos := []pathTodo{{Title: "first"}, {Title: "second"}}
+
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"user":     pathUser{Profile: profile, Tags: []string{"admin"}},
+			"settings": settings,
+			"todos":    todos,
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	data := []struct {
+		query string
+		value interface{}
+	}{
+		{query: "user.Profile.Name", value: "Artem"},
+		{query: "user.Tags[0]", value: "admin"},
+		{query: "settings.theme", value: "dark"},
+		{query: "todos[1].Title", value: "second"},
+	}
+
+	for _, el := range data {
+		value, err := s.GetWithError(el.query)
+		if err != nil {
+			t.Errorf("unexpected error for query '%s': %s", el.query, err.Error())
+			continue
+		}
+
+		if value != el.value {
+			t.Errorf("invalid value for query '%s' want: %v, got: %v", el.query, el.value, value)
+		}
+	}
+
+	for _, query := range []string{"user.Profile.age", "user.Tags[1]", "settings.undefined", "todos[1].Title.Len"} {
+		if _, err := s.GetWithError(query); err == nil {
+			t.Errorf("expected error for query: %s", query)
+		}
+	}
+
+	err = s.UpdateStore(map[string]interface{}{
+		"user.Profile.Name": "Elen",
+		"settings.lang":     "en",
+		"todos[0].Done":     true,
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if s.Get("user.Profile.Name") != "Elen" || s.Get("settings.lang") != "en" || s.Get("todos[0].Done") != true {
+		t.Errorf("invalid values after update: %v", s.Data)
+	}
+
+	// old values must stay untouched
+	if profile.Name != "Artem" || len(settings) != 1 || todos[0].Done {
+		t.Error("update changed old values")
+	}
+
+	// only modules Data fields keep their types
+	if err := s.UpdateStore(map[string]interface{}{"settings.theme": 1}); err != nil || s.Get("settings.theme") != 1 {
+		t.Errorf("can't change type of map value: %v", err)
+	}
+
+	for _, updates := range []map[string]interface{}{
+		{"user.Profile.Name": 1},
+		{"todos[5].Done": true},
+		{"undefined.field": true},
+		{"user.Profile.age": 1},
+	} {
+		if err := s.UpdateStore(updates); err == nil {
+			t.Errorf("expected error for updates: %v", updates)
+		}
+	}
+}
@@ -0,0 +1,518 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PersistVersionKey key (after Persist.Prefix) for persisted data version
+const PersistVersionKey = "__version"
+
+// Storage key-value storage for persisting Store.Data
+type Storage interface {
+	Get(key string) (value string, ok bool, err error)
+	Set(key, value string) error
+	Remove(key string) error
+}
+
+// StorageLister Storage which can list stored keys.
+// Persist with non-empty Prefix uses it to give migrations fields removed from Store.Data
+type StorageLister interface {
+	Keys() ([]string, error)
+}
+
+// Migration migrate persisted data from one version to the next.
+// data contains persisted fields decoded without type information (numbers are float64)
+type Migration func(data map[string]interface{}) error
+
+// MemoryStorage in-memory Storage. Use it in tests
+type MemoryStorage struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// NewMemoryStorage create empty MemoryStorage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{values: make(map[string]string)}
+}
+
+// Get return value by key
+func (m *MemoryStorage) Get(key string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.values[key]
+	return value, ok, nil
+}
+
+// Set set value by key
+func (m *MemoryStorage) Set(key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[key] = value
+	return nil
+}
+
+// Remove remove value by key
+func (m *MemoryStorage) Remove(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.values, key)
+	return nil
+}
+
+// Keys return all stored keys in sorted order
+func (m *MemoryStorage) Keys() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.values))
+	for key := range m.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// Persist persisting Store.Data fields to Storage. Every field is stored as JSON by key Prefix+field name.
+//
+// Use Persist.OnCreate in Store.OnCreate and Persist.AfterEmit in Store.AfterEmit:
+//
+// p := &store.Persist{Storage: store.LocalStorage(), Prefix: "app.", Debounce: time.Second}
+// s, err := store.New(&store.Store{..., OnCreate: []store.OnCreateHook{p.OnCreate}, AfterEmit: []store.AfterEmitHook{p.AfterEmit}})
+type Persist struct {
+	Storage Storage
+	Prefix  string
+
+	Version    int               // persisted data version, stored with data
+	Migrations map[int]Migration // migrations by version they migrate from
+
+	Whitelist []string // persist only these fields
+	Blacklist []string // don't persist these fields
+
+	OnlyChanged bool // write only fields changed by event
+
+	Debounce time.Duration // write after Debounce without new events, timers are created by Store.Clock
+	Throttle time.Duration // write not more often than once per Throttle
+
+	OnError func(err error) // called for errors in writes
+
+	mu        sync.Mutex
+	pending   map[string]bool
+	timer     Timer
+	lastWrite time.Time
+}
+
+// OnCreate load persisted fields to Store.Data. Persisted data older than Persist.Version is migrated first.
+// Fields are decoded into types declared in Store.Schema or into types of default Data values
+func (p *Persist) OnCreate(s *Store) error {
+	if p.Storage == nil {
+		return errors.New("persist storage is nil")
+	}
+
+	version, err := p.storedVersion()
+	if err != nil {
+		return err
+	}
+
+	if version > p.Version {
+		return fmt.Errorf("persisted data version %d is newer than %d", version, p.Version)
+	}
+
+	if version < p.Version {
+		return p.migrate(s, version)
+	}
+
+	for name := range s.Data {
+		if !p.allowed(name) {
+			continue
+		}
+
+		raw, ok, err := p.Storage.Get(p.Prefix + name)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		decoded, err := decodeField(raw, s.fieldType(name))
+		if err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+
+		s.Data[name] = decoded
+	}
+
+	return nil
+}
+
+// migrate run migrations from version to Persist.Version and save migrated data
+func (p *Persist) migrate(s *Store, version int) error {
+	data, err := p.loadRaw(s)
+	if err != nil {
+		return err
+	}
+
+	loaded := make([]string, 0, len(data))
+	for name := range data {
+		loaded = append(loaded, name)
+	}
+
+	for v := version; v < p.Version; v++ {
+		migration, ok := p.Migrations[v]
+		if !ok {
+			if len(data) == 0 {
+				continue
+			}
+
+			return fmt.Errorf("migration from version %d is undefined", v)
+		}
+
+		if err := migration(data); err != nil {
+			return fmt.Errorf("migration from version %d: %s", v, err.Error())
+		}
+	}
+
+	for _, name := range loaded {
+		if _, ok := data[name]; !ok {
+			if err := p.Storage.Remove(p.Prefix + name); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, value := range data {
+		if _, ok := s.Data[name]; !ok || !p.allowed(name) {
+			if err := p.Storage.Remove(p.Prefix + name); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+
+		decoded, err := decodeField(string(raw), s.fieldType(name))
+		if err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+
+		if err := p.Storage.Set(p.Prefix+name, string(raw)); err != nil {
+			return err
+		}
+
+		s.Data[name] = decoded
+	}
+
+	return p.Storage.Set(p.Prefix+PersistVersionKey, strconv.Itoa(p.Version))
+}
+
+// loadRaw load persisted fields without type information
+func (p *Persist) loadRaw(s *Store) (map[string]interface{}, error) {
+	var names []string
+	if lister, ok := p.Storage.(StorageLister); ok && len(p.Prefix) != 0 {
+		keys, err := lister.Keys()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			if strings.HasPrefix(key, p.Prefix) && key != p.Prefix+PersistVersionKey {
+				names = append(names, strings.TrimPrefix(key, p.Prefix))
+			}
+		}
+	} else {
+		for name := range s.Data {
+			names = append(names, name)
+		}
+	}
+
+	data := make(map[string]interface{})
+	for _, name := range names {
+		raw, ok, err := p.Storage.Get(p.Prefix + name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, &FieldError{Field: name, Err: err}
+		}
+
+		data[name] = value
+	}
+
+	return data, nil
+}
+
+// importLegacy move fields stored as one JSON object by key to Persist keys and remove key.
+// Fields already stored by Persist aren't overwritten
+func (p *Persist) importLegacy(key string) error {
+	raw, ok, err := p.Storage.Get(key)
+	if err != nil || !ok {
+		return err
+	}
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return fmt.Errorf("invalid legacy data by key %s: %s", key, err.Error())
+	}
+
+	for name, value := range data {
+		_, ok, err := p.Storage.Get(p.Prefix + name)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			continue
+		}
+
+		if err := p.Storage.Set(p.Prefix+name, string(value)); err != nil {
+			return err
+		}
+	}
+
+	return p.Storage.Remove(key)
+}
+
+func (p *Persist) storedVersion() (int, error) {
+	raw, ok, err := p.Storage.Get(p.Prefix + PersistVersionKey)
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	version, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid persisted data version '%s'", raw)
+	}
+
+	return version, nil
+}
+
+// AfterEmit write changed fields to Storage after event is committed, so rolled back Data isn't persisted.
+// With Debounce or Throttle writes are delayed. Write errors are passed to OnError
+func (p *Persist) AfterEmit(s *Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+	var names []string
+	if p.OnlyChanged {
+		for key := range updatesMap {
+			names = append(names, fieldName(key))
+		}
+	} else {
+		s.mu.RLock()
+		for name := range s.Data {
+			names = append(names, name)
+		}
+		s.mu.RUnlock()
+	}
+
+	s.onCommit(func() {
+		if err := p.write(s, names); err != nil && p.OnError != nil {
+			p.OnError(err)
+		}
+	})
+
+	return nil
+}
+
+// write write fields of committed event or schedule delayed write
+func (p *Persist) write(s *Store, names []string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pending == nil {
+		p.pending = make(map[string]bool)
+	}
+
+	for _, name := range names {
+		p.pending[name] = true
+	}
+
+	switch {
+	case p.Debounce > 0:
+		if p.timer != nil {
+			p.timer.Stop()
+		}
+
+		p.timer = s.clock().AfterFunc(p.Debounce, func() { p.delayedFlush(s) })
+		return nil
+	case p.Throttle > 0:
+		wait := p.Throttle - s.clock().Now().Sub(p.lastWrite)
+		if wait > 0 {
+			if p.timer == nil {
+				p.timer = s.clock().AfterFunc(wait, func() { p.delayedFlush(s) })
+			}
+
+			return nil
+		}
+	}
+
+	return p.flush(s)
+}
+
+// Flush write all delayed fields now
+func (p *Persist) Flush(s *Store) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+
+	return p.flush(s)
+}
+
+func (p *Persist) delayedFlush(s *Store) {
+	p.mu.Lock()
+	p.timer = nil
+	err := p.flush(s)
+	p.mu.Unlock()
+
+	if err != nil && p.OnError != nil {
+		p.OnError(err)
+	}
+}
+
+// flush write pending fields. p.mu must be locked
+func (p *Persist) flush(s *Store) error {
+	if p.Storage == nil {
+		return errors.New("persist storage is nil")
+	}
+
+	names := make([]string, 0, len(p.pending))
+	for name := range p.pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p.pending = make(map[string]bool)
+	p.lastWrite = s.clock().Now()
+
+	if len(names) != 0 {
+		err := p.Storage.Set(p.Prefix+PersistVersionKey, strconv.Itoa(p.Version))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		if !p.allowed(name) {
+			continue
+		}
+
+		s.mu.RLock()
+		value, ok := s.Data[name]
+		s.mu.RUnlock()
+
+		if !ok {
+			err := p.Storage.Remove(p.Prefix + name)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+
+		err = p.Storage.Set(p.Prefix+name, string(raw))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Persist) allowed(name string) bool {
+	return allowedField(name, p.Whitelist, p.Blacklist)
+}
+
+// allowedField return true if field isn't in blacklist and whitelist is nil or contains field
+func allowedField(name string, whitelist, blacklist []string) bool {
+	for _, el := range blacklist {
+		if el == name {
+			return false
+		}
+	}
+
+	if whitelist == nil {
+		return true
+	}
+
+	for _, el := range whitelist {
+		if el == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldName return Data field name for updatesMap key: "user.name" => "user"
+func fieldName(key string) string {
+	if !isPath(key) {
+		return key
+	}
+
+	path, err := parsePath(key)
+	if err != nil {
+		return key
+	}
+
+	return path[0]
+}
+
+// fieldType return type persisted field must be decoded into.
+// Schema type is used if it's concrete, otherwise type of current value
+func (s *Store) fieldType(name string) reflect.Type {
+	if field, ok := s.Schema[name]; ok && field.Type != nil && field.Type.Kind() != reflect.Interface {
+		return field.Type
+	}
+
+	if value := s.Data[name]; value != nil {
+		return reflect.TypeOf(value)
+	}
+
+	return nil
+}
+
+// decodeField decode JSON into value of typ. If typ is nil, JSON will be decoded into interface{}
+func decodeField(raw string, typ reflect.Type) (interface{}, error) {
+	if typ == nil {
+		var value interface{}
+		err := json.Unmarshal([]byte(raw), &value)
+		return value, err
+	}
+
+	value := reflect.New(typ)
+	err := json.Unmarshal([]byte(raw), value.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	return value.Elem().Interface(), nil
+}
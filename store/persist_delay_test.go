@@ -0,0 +1,76 @@
+package store_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gascore/std/store"
+	"github.com/gascore/std/store/storetest"
+)
+
+func TestPersistDelayed(t *testing.T) {
+	data := []struct {
+		name    string
+		persist *store.Persist
+		steps   []time.Duration // clock advances after every emit
+		written []string        // persisted counter after every step
+	}{
+		{
+			name:    "debounce",
+			persist: &store.Persist{Debounce: 100 * time.Millisecond},
+			steps:   []time.Duration{50 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond},
+			written: []string{"", "", "3"},
+		},
+		{
+			name:    "throttle",
+			persist: &store.Persist{Throttle: 100 * time.Millisecond},
+			steps:   []time.Duration{0, 50 * time.Millisecond, 50 * time.Millisecond},
+			written: []string{"1", "1", "3"},
+		},
+	}
+
+	for _, el := range data {
+		storage := store.NewMemoryStorage()
+		clock := storetest.NewFakeClock(time.Unix(0, 0))
+
+		el.persist.Storage = storage
+		el.persist.Whitelist = []string{"counter"}
+
+		s, err := store.New(&store.Store{
+			Data: map[string]interface{}{"counter": 0, "token": ""},
+			Handlers: map[string]store.Handler{
+				"inc": func(s *store.Store, values ...interface{}) (map[string]interface{}, error) {
+					return map[string]interface{}{"counter": s.Get("counter").(int) + 1}, nil
+				},
+			},
+			Clock:     clock,
+			OnCreate:  []store.OnCreateHook{el.persist.OnCreate},
+			AfterEmit: []store.AfterEmitHook{el.persist.AfterEmit},
+		})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", el.name, err.Error())
+			continue
+		}
+
+		for i, step := range el.steps {
+			if err := s.Emit("inc"); err != nil {
+				t.Errorf("%s: unexpected error: %s", el.name, err.Error())
+			}
+
+			clock.Advance(step)
+
+			if value, _, _ := storage.Get("counter"); value != el.written[i] {
+				t.Errorf("%s: step %d: invalid persisted counter want: %q, got: %q", el.name, i, el.written[i], value)
+			}
+		}
+
+		if keys, _ := storage.Keys(); strings.Join(keys, ",") != "__version,counter" {
+			t.Errorf("%s: invalid persisted keys: %v", el.name, keys)
+		}
+
+		if clock.Timers() != 0 {
+			t.Errorf("%s: timers weren't stopped: %d", el.name, clock.Timers())
+		}
+	}
+}
@@ -0,0 +1,269 @@
+//go:build js && wasm
+// +build js,wasm
+
+package store
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall/js"
+
+	"github.com/gascore/dom/storage"
+)
+
+// LocalStorage return Storage for window.localStorage
+func LocalStorage() Storage {
+	return &webStorage{get: storage.Local}
+}
+
+// SessionStorage return Storage for window.sessionStorage
+func SessionStorage() Storage {
+	return &webStorage{get: storage.Session}
+}
+
+type webStorage struct {
+	get func() storage.Storage
+}
+
+var errStorageUnavailable = errors.New("web storage is unavailable")
+
+func (w *webStorage) Get(key string) (string, bool, error) {
+	st := w.get()
+	if st == nil {
+		return "", false, errStorageUnavailable
+	}
+
+	value, ok := st.GetItem(key)
+	return value, ok, nil
+}
+
+func (w *webStorage) Set(key, value string) error {
+	st := w.get()
+	if st == nil {
+		return errStorageUnavailable
+	}
+
+	st.SetItem(key, value)
+	return nil
+}
+
+func (w *webStorage) Remove(key string) error {
+	st := w.get()
+	if st == nil {
+		return errStorageUnavailable
+	}
+
+	st.RemoveItem(key)
+	return nil
+}
+
+func (w *webStorage) Keys() ([]string, error) {
+	st := w.get()
+	if st == nil {
+		return nil, errStorageUnavailable
+	}
+
+	keys := make([]string, st.Length())
+	for i := range keys {
+		keys[i] = st.Key(i)
+	}
+
+	return keys, nil
+}
+
+// CookieStorage return Storage for document.cookie. Values are query escaped, cookies are set for all paths for a year
+func CookieStorage() Storage {
+	return cookieStorage{}
+}
+
+type cookieStorage struct{}
+
+func (cookieStorage) document() (js.Value, error) {
+	document := js.Global().Get("document")
+	if !document.Truthy() {
+		return js.Value{}, errors.New("document is unavailable")
+	}
+
+	return document, nil
+}
+
+func (c cookieStorage) Get(key string) (string, bool, error) {
+	document, err := c.document()
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, pair := range strings.Split(document.Get("cookie").String(), ";") {
+		pair = strings.TrimSpace(pair)
+		if !strings.HasPrefix(pair, key+"=") {
+			continue
+		}
+
+		value, err := url.QueryUnescape(pair[len(key)+1:])
+		if err != nil {
+			return "", false, err
+		}
+
+		return value, true, nil
+	}
+
+	return "", false, nil
+}
+
+func (c cookieStorage) Set(key, value string) error {
+	document, err := c.document()
+	if err != nil {
+		return err
+	}
+
+	document.Set("cookie", key+"="+url.QueryEscape(value)+"; path=/; max-age=31536000")
+	return nil
+}
+
+func (c cookieStorage) Remove(key string) error {
+	document, err := c.document()
+	if err != nil {
+		return err
+	}
+
+	document.Set("cookie", key+"=; path=/; max-age=0")
+	return nil
+}
+
+// IndexedDB return Storage for IndexedDB object store. Database and object store will be created if they don't exist.
+// IndexedDB and Get wait for results, so don't call them from JS callbacks. Set and Remove don't wait,
+// so Persist can write from events emitted by JS callbacks. Failed write error is returned by the next call
+func IndexedDB(dbName, storeName string) (Storage, error) {
+	factory := js.Global().Get("indexedDB")
+	if !factory.Truthy() {
+		return nil, errors.New("indexedDB is unavailable")
+	}
+
+	req := factory.Call("open", dbName)
+
+	upgrade := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		req.Get("result").Call("createObjectStore", storeName)
+		return nil
+	})
+	defer upgrade.Release()
+	req.Set("onupgradeneeded", upgrade)
+
+	db, err := waitRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &indexedDB{db: db, storeName: storeName}, nil
+}
+
+type indexedDB struct {
+	db        js.Value
+	storeName string
+
+	mu  sync.Mutex
+	err error // failed write error
+}
+
+func (i *indexedDB) objectStore(mode string) js.Value {
+	return i.db.Call("transaction", i.storeName, mode).Call("objectStore", i.storeName)
+}
+
+func (i *indexedDB) Get(key string) (string, bool, error) {
+	if err := i.writeErr(); err != nil {
+		return "", false, err
+	}
+
+	value, err := waitRequest(i.objectStore("readonly").Call("get", key))
+	if err != nil {
+		return "", false, err
+	}
+
+	if t := value.Type(); t == js.TypeUndefined || t == js.TypeNull {
+		return "", false, nil
+	}
+
+	return value.String(), true, nil
+}
+
+func (i *indexedDB) Set(key, value string) error {
+	return i.write("put", value, key)
+}
+
+func (i *indexedDB) Remove(key string) error {
+	return i.write("delete", key)
+}
+
+// write send write request without waiting for it. Requests to object store are done in order
+func (i *indexedDB) write(method string, args ...interface{}) error {
+	if err := i.writeErr(); err != nil {
+		return err
+	}
+
+	req := i.objectStore("readwrite").Call(method, args...)
+
+	var onSuccess, onError js.Func
+	release := func() {
+		onSuccess.Release()
+		onError.Release()
+	}
+
+	onSuccess = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		release()
+		return nil
+	})
+
+	onError = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		i.mu.Lock()
+		if i.err == nil {
+			i.err = errors.New("indexedDB: " + req.Get("error").Get("message").String())
+		}
+		i.mu.Unlock()
+
+		release()
+		return nil
+	})
+
+	req.Set("onsuccess", onSuccess)
+	req.Set("onerror", onError)
+
+	return nil
+}
+
+// writeErr return and forget failed write error
+func (i *indexedDB) writeErr() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	err := i.err
+	i.err = nil
+	return err
+}
+
+// waitRequest wait for IDBRequest result. It blocks until JS event loop runs request callbacks,
+// so it deadlocks if called from JS callback
+func waitRequest(req js.Value) (js.Value, error) {
+	done := make(chan error, 1)
+
+	onSuccess := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		done <- nil
+		return nil
+	})
+	defer onSuccess.Release()
+
+	onError := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		done <- errors.New("indexedDB: " + req.Get("error").Get("message").String())
+		return nil
+	})
+	defer onError.Release()
+
+	req.Set("onsuccess", onSuccess)
+	req.Set("onerror", onError)
+
+	if err := <-done; err != nil {
+		return js.Value{}, err
+	}
+
+	return req.Get("result"), nil
+}
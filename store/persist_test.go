@@ -0,0 +1,216 @@
+package store
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type persistUser struct {
+	Name string
+	Age  int
+}
+
+func newPersistStore(p *Persist) (*Store, error) {
+	return New(&Store{
+		Data: map[string]interface{}{
+			"user":    persistUser{Name: "guest"},
+			"counter": 0,
+			"token":   "",
+		},
+		Handlers: map[string]Handler{
+			"inc": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": s.Get("counter").(int) + 1}, nil
+			},
+			"login": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"user.Name": values[0], "token": "secret"}, nil
+			},
+		},
+		OnCreate:  []OnCreateHook{p.OnCreate},
+		AfterEmit: []AfterEmitHook{p.AfterEmit},
+	})
+}
+
+func storedKeys(storage *MemoryStorage) string {
+	keys, _ := storage.Keys()
+	return strings.Join(keys, ",")
+}
+
+func TestPersist(t *testing.T) {
+	storage := NewMemoryStorage()
+	p := &Persist{Storage: storage, Prefix: "app.", Blacklist: []string{"token"}, OnlyChanged: true}
+
+	s, err := newPersistStore(p)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Emit("login", "bob"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if keys := storedKeys(storage); keys != "app.__version,app.user" {
+		t.Errorf("invalid persisted keys: %s", keys)
+	}
+
+	if err := s.Emit("inc"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if keys := storedKeys(storage); keys != "app.__version,app.counter,app.user" {
+		t.Errorf("invalid persisted keys: %s", keys)
+	}
+
+	s2, err := newPersistStore(&Persist{Storage: storage, Prefix: "app."})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	user, ok := s2.Get("user").(persistUser)
+	if !ok || user.Name != "bob" {
+		t.Errorf("invalid loaded user: %#v", s2.Get("user"))
+	}
+
+	if s2.Get("counter") != 1 {
+		t.Errorf("invalid loaded counter: %#v", s2.Get("counter"))
+	}
+
+	storage.Set("app.counter", "{")
+	if _, err := newPersistStore(&Persist{Storage: storage, Prefix: "app."}); err == nil {
+		t.Error("expected error for invalid persisted value")
+	}
+}
+
+func TestPersistRollback(t *testing.T) {
+	storage := NewMemoryStorage()
+	p := &Persist{Storage: storage, Prefix: "app.", OnlyChanged: true}
+
+	s, err := New(&Store{
+		Data: map[string]interface{}{"counter": 0},
+		Handlers: map[string]Handler{
+			"set": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": values[0]}, nil
+			},
+		},
+		OnCreate: []OnCreateHook{p.OnCreate},
+		AfterEmit: []AfterEmitHook{
+			p.AfterEmit,
+			func(s *Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+				if updatesMap["counter"] == -1 {
+					return errors.New("invalid counter")
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Emit("set", -1); err == nil {
+		t.Error("expected error")
+	}
+
+	if keys := storedKeys(storage); keys != "" {
+		t.Errorf("rolled back event was persisted: %s", keys)
+	}
+
+	s.Emit("set", 2)
+	if value, _, _ := storage.Get("app.counter"); value != "2" {
+		t.Errorf("invalid persisted counter: %s", value)
+	}
+}
+
+func TestPersistImportLegacy(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.Set("data", `{"counter": 3, "token": "old"}`)
+	storage.Set("data.token", `"new"`)
+
+	p := &Persist{Storage: storage, Prefix: "data."}
+	if err := p.importLegacy("data"); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	s, err := newPersistStore(p)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if s.Get("counter") != 3 || s.Get("token") != "new" {
+		t.Errorf("invalid imported data: counter: %#v, token: %#v", s.Get("counter"), s.Get("token"))
+	}
+
+	if _, ok, _ := storage.Get("data"); ok {
+		t.Error("legacy key wasn't removed")
+	}
+
+	storage.Set("data", "[")
+	if err := p.importLegacy("data"); err == nil {
+		t.Error("expected error for invalid legacy data")
+	}
+}
+
+func TestPersistMigrations(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.Set("app.count", "5")
+	storage.Set("app.user", `{"Name":"bob"}`)
+
+	p := &Persist{
+		Storage: storage,
+		Prefix:  "app.",
+		Version: 2,
+		Migrations: map[int]Migration{
+			0: func(data map[string]interface{}) error {
+				data["counter"] = data["count"]
+				delete(data, "count")
+				return nil
+			},
+			1: func(data map[string]interface{}) error {
+				user := data["user"].(map[string]interface{})
+				age, ok := user["Age"].(float64)
+				if !ok {
+					return errors.New("user age is undefined")
+				}
+
+				user["Age"] = age + 18
+				return nil
+			},
+		},
+	}
+
+	s, err := newPersistStore(p)
+	if err == nil {
+		t.Error("expected error for failed migration")
+		return
+	}
+
+	storage.Set("app.user", `{"Name":"bob","Age":0}`)
+	s, err = newPersistStore(p)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if s.Get("counter") != 5 {
+		t.Errorf("counter wasn't decoded as int: %#v", s.Get("counter"))
+	}
+
+	if user, ok := s.Get("user").(persistUser); !ok || user.Age != 18 {
+		t.Errorf("invalid migrated user: %#v", s.Get("user"))
+	}
+
+	if keys := storedKeys(storage); keys != "app.__version,app.counter,app.user" {
+		t.Errorf("invalid persisted keys: %s", keys)
+	}
+
+	if _, err := newPersistStore(&Persist{Storage: storage, Prefix: "app.", Version: 1}); err == nil {
+		t.Error("expected error for newer persisted data")
+	}
+}
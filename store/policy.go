@@ -0,0 +1,265 @@
+package store
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Clock time source for delayed events. Use storetest.FakeClock in tests
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer timer created by Clock.AfterFunc
+type Timer interface {
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func (s *Store) clock() Clock {
+	if s.Clock == nil {
+		return realClock{}
+	}
+
+	return s.Clock
+}
+
+const (
+	policyDebounce = iota
+	policyThrottle
+	policyDropInFlight
+	policyDedupe
+)
+
+// Policy event emitting policy. Use it in Handlers.Add
+type Policy struct {
+	kind     int
+	duration time.Duration
+}
+
+// Debounce run handler only after d without new emits with last emitted values
+func Debounce(d time.Duration) Policy {
+	return Policy{kind: policyDebounce, duration: d}
+}
+
+// Throttle run handler not more often than once per d. Emits inside interval are dropped,
+// except last one which will be run at interval end
+func Throttle(d time.Duration) Policy {
+	return Policy{kind: policyThrottle, duration: d}
+}
+
+// DropIfInFlight drop emit if same event is processing in other goroutine or is waiting for debounce or throttle
+func DropIfInFlight() Policy {
+	return Policy{kind: policyDropInFlight}
+}
+
+// Dedupe drop emit with same values as previous accepted emit
+func Dedupe() Policy {
+	return Policy{kind: policyDedupe}
+}
+
+// withPolicies wrap handler by policies. Debounce and Throttle can't be combined, the first one is used.
+// Delayed handlers run without hooks and middlewares (they were called on emit), their errors are passed to Store.OnError
+func withPolicies(handler Handler, policies []Policy) Handler {
+	p := &policyHandler{handler: handler}
+	for _, policy := range policies {
+		switch policy.kind {
+		case policyDebounce, policyThrottle:
+			if p.delay == nil {
+				policy := policy
+				p.delay = &policy
+			}
+		case policyDropInFlight:
+			p.dropInFlight = true
+		case policyDedupe:
+			p.dedupe = true
+		}
+	}
+
+	return p.handle
+}
+
+type policyHandler struct {
+	handler Handler
+
+	delay        *Policy
+	dropInFlight bool
+	dedupe       bool
+
+	mu sync.Mutex
+}
+
+// policyState state of policyHandler in one store. The same Handlers can be used by many stores, so it's kept in Store
+type policyState struct {
+	event string
+
+	last    []interface{} // last accepted values
+	hasLast bool
+
+	timer   Timer
+	gen     int           // current timer generation, stale timers don't run
+	pending []interface{} // values for delayed run
+	until   time.Time     // throttle interval end
+}
+
+// handle called by store under emitMu
+func (p *policyHandler) handle(s *Store, values ...interface{}) (map[string]interface{}, error) {
+	e := s.current
+	if e == nil || e.replay {
+		return p.handler(s, values...)
+	}
+
+	st, ok := s.policyStates[p]
+	if !ok {
+		if s.policyStates == nil {
+			s.policyStates = make(map[*policyHandler]*policyState)
+		}
+
+		st = &policyState{}
+		s.policyStates[p] = st
+	}
+
+	p.mu.Lock()
+	st.event = e.Name
+
+	if p.dropInFlight && (e.busy || st.timer != nil) {
+		p.mu.Unlock()
+		return nil, nil
+	}
+
+	if p.dedupe && st.hasLast && reflect.DeepEqual(st.last, values) {
+		p.mu.Unlock()
+		return nil, nil
+	}
+
+	if p.delay == nil {
+		p.mu.Unlock()
+		p.acceptOnCommit(s, st, values)
+		return p.handler(s, values...)
+	}
+
+	clock := s.clock()
+	switch p.delay.kind {
+	case policyDebounce:
+		if st.timer != nil {
+			st.timer.Stop()
+		}
+
+		st.pending = values
+		st.timer = p.schedule(s, st, p.delay.duration)
+		p.mu.Unlock()
+
+		return nil, nil
+	default: // throttle
+		now := clock.Now()
+		if !now.Before(st.until) && st.timer == nil {
+			st.until = now.Add(p.delay.duration)
+			p.mu.Unlock()
+
+			p.acceptOnCommit(s, st, values)
+			return p.handler(s, values...)
+		}
+
+		st.pending = values
+		if st.timer == nil {
+			st.timer = p.schedule(s, st, st.until.Sub(now))
+		}
+		p.mu.Unlock()
+
+		return nil, nil
+	}
+}
+
+// schedule create timer for delayed run. Timer which was replaced by the next one (even if Stop failed
+// because timer had already fired) won't run handler. p.mu must be locked
+func (p *policyHandler) schedule(s *Store, st *policyState, d time.Duration) Timer {
+	st.gen++
+	gen := st.gen
+
+	return s.clock().AfterFunc(d, func() { p.fire(s, st, gen) })
+}
+
+// fire run delayed handler and commit its updates
+func (p *policyHandler) fire(s *Store, st *policyState, gen int) {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	p.mu.Lock()
+	if gen != st.gen || st.timer == nil {
+		p.mu.Unlock()
+		return // stale timer
+	}
+
+	event, values := st.event, st.pending
+	st.timer, st.pending = nil, nil
+	if p.delay.kind == policyThrottle {
+		st.until = s.clock().Now().Add(p.delay.duration)
+	}
+	p.mu.Unlock()
+
+	updatesMap, err := p.handler(s, values...)
+	if err == nil && updatesMap != nil {
+		s.mu.RLock()
+		module, _ := s.moduleOf(event)
+		s.mu.RUnlock()
+
+		if module != nil {
+			updatesMap = module.toStoreKeys(updatesMap)
+		}
+
+		p.acceptOnCommit(s, st, values)
+		err = s.commit(event, updatesMap, values)
+	}
+
+	if err != nil && s.OnError != nil {
+		s.OnError(err)
+	}
+}
+
+// acceptOnCommit remember values for Dedupe after they are committed,
+// so emit with the same values after failed commit isn't dropped. emitMu must be locked
+func (p *policyHandler) acceptOnCommit(s *Store, st *policyState, values []interface{}) {
+	if !p.dedupe {
+		return
+	}
+
+	s.onCommit(func() {
+		p.mu.Lock()
+		st.last, st.hasLast = values, true
+		p.mu.Unlock()
+	})
+}
+
+// enter mark event as processing. Return true if the same event is already processing
+func (s *Store) enter(query string) bool {
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+
+	if s.inflight == nil {
+		s.inflight = make(map[string]int)
+	}
+
+	s.inflight[query]++
+	return s.inflight[query] > 1
+}
+
+func (s *Store) leave(query string) {
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+
+	s.inflight[query]--
+	if s.inflight[query] == 0 {
+		delete(s.inflight, query)
+	}
+}
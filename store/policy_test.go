@@ -0,0 +1,208 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gascore/std/store"
+	"github.com/gascore/std/store/storetest"
+)
+
+func newPolicyStore(t *testing.T, clock *storetest.FakeClock, policies ...store.Policy) (*store.Store, *storetest.Recorder, *int) {
+	calls := new(int)
+
+	handlers := store.NewHandlers()
+	handlers.Add("search", func(s *store.Store, values ...interface{}) (map[string]interface{}, error) {
+		*calls++
+		return map[string]interface{}{"query": values[0]}, nil
+	}, policies...)
+
+	s, r, err := storetest.NewStore(&store.Store{
+		Data:     map[string]interface{}{"query": ""},
+		Handlers: handlers,
+		Clock:    clock,
+		OnError: func(err error) {
+			t.Errorf("unexpected error: %s", err.Error())
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return s, r, calls
+}
+
+func TestPolicies(t *testing.T) {
+	data := []struct {
+		name     string
+		policies []store.Policy
+		steps    []interface{} // string values are emitted, durations advance clock
+		calls    int
+		query    string
+	}{
+		{
+			name:     "debounce",
+			policies: []store.Policy{store.Debounce(100 * time.Millisecond)},
+			steps:    []interface{}{"g", 50 * time.Millisecond, "ga", 50 * time.Millisecond, "gas", 100 * time.Millisecond},
+			calls:    1,
+			query:    "gas",
+		},
+		{
+			name:     "throttle",
+			policies: []store.Policy{store.Throttle(100 * time.Millisecond)},
+			steps:    []interface{}{"g", "ga", "gas", 100 * time.Millisecond, "gas!", 50 * time.Millisecond},
+			calls:    2,
+			query:    "gas",
+		},
+		{
+			name:     "throttle trailing",
+			policies: []store.Policy{store.Throttle(100 * time.Millisecond)},
+			steps:    []interface{}{"g", "ga", "gas", 100 * time.Millisecond, "gas!", 100 * time.Millisecond},
+			calls:    3,
+			query:    "gas!",
+		},
+		{
+			name:     "dedupe",
+			policies: []store.Policy{store.Dedupe()},
+			steps:    []interface{}{"g", "g", "ga", "ga", "g"},
+			calls:    3,
+			query:    "g",
+		},
+		{
+			name:     "drop if in flight",
+			policies: []store.Policy{store.Debounce(100 * time.Millisecond), store.DropIfInFlight()},
+			steps:    []interface{}{"g", "ga", 100 * time.Millisecond, "gas", 100 * time.Millisecond},
+			calls:    2,
+			query:    "gas",
+		},
+	}
+
+	for _, el := range data {
+		clock := storetest.NewFakeClock(time.Unix(0, 0))
+		s, r, calls := newPolicyStore(t, clock, el.policies...)
+
+		for _, step := range el.steps {
+			switch step := step.(type) {
+			case string:
+				if err := s.Emit("search", step); err != nil {
+					t.Errorf("%s: unexpected error: %s", el.name, err.Error())
+				}
+			case time.Duration:
+				clock.Advance(step)
+			}
+		}
+
+		if *calls != el.calls || s.Get("query") != el.query {
+			t.Errorf("%s: want: %d calls and query %q, got: %d calls and query %q", el.name, el.calls, el.query, *calls, s.Get("query"))
+		}
+
+		if len(r.Events()) != el.calls {
+			t.Errorf("%s: invalid committed events: %v", el.name, r.Names())
+		}
+	}
+}
+
+func TestDedupeRetry(t *testing.T) {
+	calls, fail := 0, true
+
+	handlers := store.NewHandlers()
+	handlers.Add("search", func(s *store.Store, values ...interface{}) (map[string]interface{}, error) {
+		calls++
+		return map[string]interface{}{"query": values[0]}, nil
+	}, store.Dedupe())
+
+	s, err := store.New(&store.Store{
+		Data:     map[string]interface{}{"query": ""},
+		Handlers: handlers,
+		AfterEmit: []store.AfterEmitHook{
+			func(s *store.Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+				if fail {
+					fail = false
+					return errors.New("commit failed")
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := s.Emit("search", "gas"); err == nil {
+		t.Error("expected error")
+	}
+
+	// retry after failed commit isn't deduped
+	for i := 0; i < 2; i++ {
+		if err := s.Emit("search", "gas"); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+	}
+
+	if calls != 2 || s.Get("query") != "gas" {
+		t.Errorf("want: 2 calls and query %q, got: %d calls and query %q", "gas", calls, s.Get("query"))
+	}
+}
+
+// lateClock clock which timers have always fired already: Stop returns false, functions are called by fire
+type lateClock struct {
+	funcs []func()
+}
+
+func (c *lateClock) Now() time.Time {
+	return time.Unix(0, 0)
+}
+
+func (c *lateClock) AfterFunc(d time.Duration, f func()) store.Timer {
+	c.funcs = append(c.funcs, f)
+	return lateTimer{}
+}
+
+func (c *lateClock) fire() {
+	funcs := c.funcs
+	c.funcs = nil
+	for _, f := range funcs {
+		f()
+	}
+}
+
+type lateTimer struct{}
+
+func (lateTimer) Stop() bool {
+	return false
+}
+
+func TestDebounceLateTimer(t *testing.T) {
+	var calls [][]interface{}
+
+	handlers := store.NewHandlers()
+	handlers.Add("search", func(s *store.Store, values ...interface{}) (map[string]interface{}, error) {
+		calls = append(calls, values)
+		return map[string]interface{}{"query": values[0]}, nil
+	}, store.Debounce(100*time.Millisecond))
+
+	clock := &lateClock{}
+	s, err := store.New(&store.Store{
+		Data:     map[string]interface{}{"query": ""},
+		Handlers: handlers,
+		Clock:    clock,
+		OnError: func(err error) {
+			t.Errorf("unexpected error: %s", err.Error())
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	// the first timer fires after it was replaced by the second one
+	s.Emit("search", "a")
+	s.Emit("search", "b")
+	clock.fire()
+
+	if len(calls) != 1 || len(calls[0]) != 1 || calls[0][0] != "b" || s.Get("query") != "b" {
+		t.Errorf("invalid handler calls want: [[b]], got: %v", calls)
+	}
+}
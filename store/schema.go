@@ -0,0 +1,102 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Schema declared Store.Data fields
+type Schema map[string]Field
+
+// Field describe one Store.Data field
+type Field struct {
+	Type    reflect.Type // if Type is nil, type of Default will be used
+	Default interface{}
+
+	Nullable bool // field can be nil
+
+	Validator func(value interface{}) error
+}
+
+// FieldError error for invalid field value in updatesMap
+type FieldError struct {
+	Event string // empty if error wasn't caused by event
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	if len(e.Event) == 0 {
+		return fmt.Sprintf("field '%s': %s", e.Field, e.Err.Error())
+	}
+
+	return fmt.Sprintf("event '%s': field '%s': %s", e.Event, e.Field, e.Err.Error())
+}
+
+// TypeOf return type of value ptr points to. Use it for declaring interface fields:
+//
+// Field{Type: store.TypeOf((*fmt.Stringer)(nil))}
+func TypeOf(ptr interface{}) reflect.Type {
+	return reflect.TypeOf(ptr).Elem()
+}
+
+// check validate value for field
+func (f Field) check(value interface{}) error {
+	if value == nil {
+		if !f.Nullable {
+			return fmt.Errorf("field isn't nullable")
+		}
+
+		return nil
+	}
+
+	if !reflect.TypeOf(value).AssignableTo(f.Type) {
+		return fmt.Errorf("uncompared types: %T and %s", value, f.Type.String())
+	}
+
+	if f.Validator != nil {
+		return f.Validator(value)
+	}
+
+	return nil
+}
+
+// initSchema resolve fields types and set default values to Store.Data
+func (s *Store) initSchema() error {
+	if s.Data == nil {
+		s.Data = make(map[string]interface{})
+	}
+
+	for name, field := range s.Schema {
+		if field.Type == nil {
+			if field.Default == nil {
+				return fmt.Errorf("field '%s': type is undefined", name)
+			}
+
+			field.Type = reflect.TypeOf(field.Default)
+			s.Schema[name] = field
+		}
+
+		if _, ok := s.Data[name]; !ok {
+			s.Data[name] = field.Default
+		}
+	}
+
+	return nil
+}
+
+// checkData validate all Store.Data fields by Schema
+func (s *Store) checkData() error {
+	for name, value := range s.Data {
+		field, ok := s.Schema[name]
+		if !ok {
+			return &FieldError{Field: name, Err: fmt.Errorf("field isn't declared in schema")}
+		}
+
+		if err := field.check(value); err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type schemaName string
+
+func (n schemaName) String() string { return string(n) }
+
+func TestSchema(t *testing.T) {
+	s, err := New(&Store{
+		Schema: Schema{
+			"counter": {Default: 0, Validator: func(value interface{}) error {
+				if value.(int) < 0 {
+					return errors.New("counter can't be negative")
+				}
+				return nil
+			}},
+			"user":  {Type: reflect.TypeOf(map[string]string{}), Nullable: true},
+			"title": {Type: TypeOf((*fmt.Stringer)(nil)), Default: schemaName("hello")},
+		},
+		Handlers: map[string]Handler{
+			"typo": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"countr": 1}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if s.Get("counter") != 0 || s.Get("user") != nil {
+		t.Errorf("invalid default values: %v", s.Data)
+		return
+	}
+
+	data := []struct {
+		updates map[string]interface{}
+		isValid bool
+	}{
+		{updates: map[string]interface{}{"counter": 1}, isValid: true},
+		{updates: map[string]interface{}{"counter": -1}, isValid: false},
+		{updates: map[string]interface{}{"counter": "1"}, isValid: false},
+		{updates: map[string]interface{}{"counter": nil}, isValid: false},
+		{updates: map[string]interface{}{"user": map[string]string{"name": "Artem"}}, isValid: true},
+		{updates: map[string]interface{}{"user": nil}, isValid: true},
+		{updates: map[string]interface{}{"title": schemaName("world")}, isValid: true},
+		{updates: map[string]interface{}{"title": "world"}, isValid: false},
+		{updates: map[string]interface{}{"undefined": 1}, isValid: false},
+	}
+
+	for _, el := range data {
+		err := s.UpdateStore(el.updates)
+		if (err == nil) != el.isValid {
+			t.Errorf("invalid UpdateStore result for %v: %v", el.updates, err)
+		}
+	}
+
+	err = s.Emit("typo")
+	fieldErr, ok := err.(*FieldError)
+	if !ok {
+		t.Errorf("invalid error type want: *FieldError, got: %T", err)
+		return
+	}
+
+	if fieldErr.Event != "typo" || fieldErr.Field != "countr" {
+		t.Errorf("invalid error: %s", err.Error())
+	}
+}
+
+func TestSchemaInvalidData(t *testing.T) {
+	_, err := New(&Store{
+		Data:   map[string]interface{}{"counter": 0, "undeclared": true},
+		Schema: Schema{"counter": {Default: 0}},
+	})
+	if err == nil {
+		t.Error("expected error for undeclared field")
+	}
+
+	_, err = New(&Store{
+		Schema: Schema{"user": {Nullable: true}},
+	})
+	if err == nil {
+		t.Error("expected error for field without type")
+	}
+}
@@ -0,0 +1,216 @@
+package store
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"html"
+	"reflect"
+	"sort"
+)
+
+// SnapshotVersion current snapshot format version
+const SnapshotVersion = 1
+
+// HydrateEvent event name for Data restoring by Store.Hydrate
+const HydrateEvent = "store/hydrate"
+
+// Snapshot copy of Store.Data for serializing. Gob requires registering (gob.Register) custom types stored in Data
+type Snapshot struct {
+	Version int
+	Data    map[string]interface{}
+	Types   map[string]string // Data fields types names for checking on hydrate
+}
+
+// jsonSnapshot JSON form of Snapshot
+type jsonSnapshot struct {
+	Version int                        `json:"version"`
+	Data    map[string]json.RawMessage `json:"data"`
+	Types   map[string]string          `json:"types"`
+}
+
+// Snapshot return copy of Data
+func (s *Store) Snapshot() *Snapshot {
+	data := s.Values()
+
+	types := make(map[string]string, len(data))
+	for name, value := range data {
+		if value != nil {
+			types[name] = reflect.TypeOf(value).String()
+		}
+	}
+
+	return &Snapshot{Version: SnapshotVersion, Data: data, Types: types}
+}
+
+// JSON encode snapshot to JSON
+func (sn *Snapshot) JSON() ([]byte, error) {
+	out := jsonSnapshot{Version: sn.Version, Data: make(map[string]json.RawMessage, len(sn.Data)), Types: sn.Types}
+	for name, value := range sn.Data {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, &FieldError{Field: name, Err: err}
+		}
+
+		out.Data[name] = raw
+	}
+
+	return json.Marshal(out)
+}
+
+// Gob encode snapshot to gob
+func (sn *Snapshot) Gob() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(sn)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ScriptTag return <script> tag with JSON snapshot for embedding into server rendered page.
+// Read it in browser by HydrateFromScript
+func (sn *Snapshot) ScriptTag(id string) (string, error) {
+	raw, err := sn.JSON()
+	if err != nil {
+		return "", err
+	}
+
+	// json.Marshal escapes <, > and &, so snapshot can't close script tag
+	return fmt.Sprintf(`<script type="application/json" id="%s">%s</script>`, html.EscapeString(id), raw), nil
+}
+
+// Hydrate replace Data fields by fields from JSON or gob snapshot. Fields aren't in snapshot are kept.
+// Hydrate isn't recorded to History
+func (s *Store) Hydrate(snapshot []byte) error {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	s.mu.RLock()
+	fields, err := s.decodeSnapshot(snapshot)
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return s.replay(HydrateEvent, fields)
+}
+
+// HydrateOnCreate return OnCreate hook setting Data fields from snapshot before components mount.
+// Empty snapshot is ignored
+func HydrateOnCreate(snapshot []byte) OnCreateHook {
+	return func(s *Store) error {
+		if len(snapshot) == 0 {
+			return nil
+		}
+
+		fields, err := s.decodeSnapshot(snapshot)
+		if err != nil {
+			return err
+		}
+
+		for name, value := range fields {
+			s.Data[name] = value
+		}
+
+		return nil
+	}
+}
+
+// decodeSnapshot decode snapshot fields into Data fields types. s.mu must be locked for reading
+func (s *Store) decodeSnapshot(snapshot []byte) (map[string]interface{}, error) {
+	trimmed := bytes.TrimSpace(snapshot)
+	if len(trimmed) != 0 && trimmed[0] == '{' {
+		return s.decodeJSONSnapshot(trimmed)
+	}
+
+	var sn Snapshot
+	err := gob.NewDecoder(bytes.NewReader(snapshot)).Decode(&sn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %s", err.Error())
+	}
+
+	if err := s.checkSnapshot(sn.Version, sn.Types); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{}, len(sn.Data))
+	for name, value := range sn.Data {
+		if _, ok := s.Data[name]; !ok {
+			continue
+		}
+
+		typ := s.fieldType(name)
+		if value != nil && typ != nil && !reflect.TypeOf(value).AssignableTo(typ) {
+			return nil, &FieldError{Event: HydrateEvent, Field: name, Err: fmt.Errorf("uncompared types: %T and %s", value, typ.String())}
+		}
+
+		fields[name] = value
+	}
+
+	return fields, nil
+}
+
+func (s *Store) decodeJSONSnapshot(snapshot []byte) (map[string]interface{}, error) {
+	var sn jsonSnapshot
+	err := json.Unmarshal(snapshot, &sn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %s", err.Error())
+	}
+
+	if err := s.checkSnapshot(sn.Version, sn.Types); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{}, len(sn.Data))
+	for name, raw := range sn.Data {
+		if _, ok := s.Data[name]; !ok {
+			continue
+		}
+
+		value, err := decodeField(string(raw), s.fieldType(name))
+		if err != nil {
+			return nil, &FieldError{Event: HydrateEvent, Field: name, Err: err}
+		}
+
+		fields[name] = value
+	}
+
+	return fields, nil
+}
+
+// checkSnapshot check snapshot version and that snapshot fields types are equal to Data fields types
+func (s *Store) checkSnapshot(version int, types map[string]string) error {
+	if version < 1 || version > SnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", version)
+	}
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := s.Data[name]; !ok {
+			continue
+		}
+
+		if field, ok := s.Schema[name]; ok && field.Type != nil && field.Type.Kind() == reflect.Interface {
+			continue // field can store values of different types
+		}
+
+		typ := s.fieldType(name)
+		if typ != nil && typ.String() != types[name] {
+			return &FieldError{Event: HydrateEvent, Field: name, Err: fmt.Errorf("snapshot type %s differs from %s", types[name], typ.String())}
+		}
+	}
+
+	return nil
+}
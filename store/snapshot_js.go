@@ -0,0 +1,30 @@
+//go:build js && wasm
+// +build js,wasm
+
+package store
+
+import (
+	"syscall/js"
+)
+
+// SnapshotFromScript return snapshot from <script> tag created by Snapshot.ScriptTag. ok is false if there is no such tag
+func SnapshotFromScript(id string) (snapshot []byte, ok bool) {
+	el := js.Global().Get("document").Call("getElementById", id)
+	if !el.Truthy() {
+		return nil, false
+	}
+
+	return []byte(el.Get("textContent").String()), true
+}
+
+// HydrateFromScript return OnCreate hook setting Data fields from snapshot in <script> tag. Missing tag is ignored
+func HydrateFromScript(id string) OnCreateHook {
+	return func(s *Store) error {
+		snapshot, ok := SnapshotFromScript(id)
+		if !ok {
+			return nil
+		}
+
+		return HydrateOnCreate(snapshot)(s)
+	}
+}
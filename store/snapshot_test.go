@@ -0,0 +1,113 @@
+package store
+
+import (
+	"encoding/gob"
+	"strings"
+	"testing"
+)
+
+func newSnapshotStore(onCreate ...OnCreateHook) (*Store, error) {
+	return New(&Store{
+		Data: map[string]interface{}{
+			"counter": 0,
+			"user":    persistUser{Name: "guest"},
+			"tags":    []string{},
+		},
+		OnCreate: onCreate,
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	gob.Register(persistUser{})
+
+	s, err := newSnapshotStore()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	err = s.UpdateStore(map[string]interface{}{
+		"counter": 7,
+		"user":    persistUser{Name: "bob", Age: 30},
+		"tags":    []string{"</script>"},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	snapshot := s.Snapshot()
+
+	jsonSnapshot, err := snapshot.JSON()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	gobSnapshot, err := snapshot.Gob()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	data := []struct {
+		name     string
+		snapshot []byte
+	}{
+		{name: "json", snapshot: jsonSnapshot},
+		{name: "gob", snapshot: gobSnapshot},
+	}
+
+	for _, el := range data {
+		hydrated, err := newSnapshotStore(HydrateOnCreate(el.snapshot))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", el.name, err.Error())
+			continue
+		}
+
+		if hydrated.Get("counter") != 7 {
+			t.Errorf("%s: invalid counter: %#v", el.name, hydrated.Get("counter"))
+		}
+
+		if user, ok := hydrated.Get("user").(persistUser); !ok || user.Age != 30 {
+			t.Errorf("%s: invalid user: %#v", el.name, hydrated.Get("user"))
+		}
+
+		empty, err := newSnapshotStore()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", el.name, err.Error())
+			continue
+		}
+
+		if err := empty.Hydrate(el.snapshot); err != nil {
+			t.Errorf("%s: unexpected error: %s", el.name, err.Error())
+		}
+
+		if tags := empty.Get("tags").([]string); len(tags) != 1 || tags[0] != "</script>" {
+			t.Errorf("%s: invalid tags: %#v", el.name, tags)
+		}
+	}
+
+	tag, err := snapshot.ScriptTag("state")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	if strings.Count(tag, "</script>") != 1 || !strings.HasPrefix(tag, `<script type="application/json" id="state">`) {
+		t.Errorf("invalid script tag: %s", tag)
+	}
+
+	other, err := New(&Store{Data: map[string]interface{}{"counter": ""}})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := other.Hydrate(jsonSnapshot); err == nil {
+		t.Error("expected error for snapshot with different types")
+	}
+
+	if err := other.Hydrate([]byte(`{"version":100}`)); err == nil {
+		t.Error("expected error for unsupported snapshot version")
+	}
+}
@@ -4,38 +4,89 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"strings"
+	"regexp"
+	"sort"
+	"sync"
 
 	"github.com/gascore/gas"
 )
 
-// Store main structure
+// Store main structure. Store methods are safe for concurrent use,
+// but Data and Handlers mustn't be changed directly after New
 type Store struct {
 	Data     map[string]interface{}
 	Handlers map[string]Handler
+	Actions  map[string]Action
+	Computed map[string]Computed
 
-	MiddleWares []MiddleWare
+	Modules map[string]*Module // modules mounted by namespace
+
+	Schema Schema // if Schema is nil Data fields types will be taken from their values
+
+	MiddleWares  []MiddleWare
+	Interceptors []Interceptor
 
 	OnCreate   []OnCreateHook
 	BeforeEmit []BeforeEmitHook
 	AfterEmit  []AfterEmitHook
 
-	subs []*gas.Component
+	History *History // if History isn't nil all events will be recorded
+
+	Clock   Clock           // time source for delayed events, real time if nil
+	OnError func(err error) // called for errors of delayed events (see Debounce and Throttle)
+
+	subscribers []Sub
+	bindings    []*Binding
+
+	mu     sync.RWMutex // protects Data, Handlers, subscribers and bindings
+	emitMu sync.Mutex   // serializes events processing
+
+	replaying bool // history is restoring Data, events mustn't be recorded
+
+	computedMu    sync.Mutex
+	computedCache map[string]interface{}
+	computedGen   map[string]int // increases on every invalidation
+
+	watchMu  sync.Mutex
+	watchers []*watcher
+
+	modules map[string]*Module // mounted modules
+
+	checkpoint map[string]interface{} // Data before first pending optimistic event
+	journal    []journalEntry         // events committed after checkpoint
+	optimistic *Transaction           // optimistic event being committed
+	txSeq      int
+
+	current        *Event                          // event which handler is running
+	commitHooks    []func()                        // called after current event is committed, see onCommit
+	policyStates   map[*policyHandler]*policyState // states of handlers with policies, emitMu must be locked
+	actionsRunning map[string]int                  // count of running dispatches by action, emitMu must be locked
+
+	inflightMu sync.Mutex
+	inflight   map[string]int // count of processing events by name
 }
 
 // MiddleWare let you do something before all events who have this (MiddleWare.Prefix) prefix.
 //
 // Example: { Prefix: "hello", Hook: func(s *Store) error { log.Println("Someone said hello }.
 // This middleware will trigger on events: "helloMark", "helloElen", "helloArtem", "hello*etc*"
+//
+// Events can be matched by glob Pattern ("cart/*", path.Match syntax) or Regexp instead of Prefix.
+// Middlewares with higher Priority are called first
 type MiddleWare struct {
-	Prefix string
-	Hook   func(s *Store, values []interface{}) error
+	Prefix  string
+	Pattern string
+	Regexp  *regexp.Regexp
+
+	Priority int
+
+	Hook func(s *Store, values []interface{}) error
 }
 
 // OnCreateHook called when store initializing
 type OnCreateHook func(s *Store) error
 
-// BeforeEmitHook called before event was processed
+// BeforeEmitHook called before event was processed. Hook can cancel event by returning error
 type BeforeEmitHook func(store *Store, eventName string, values []interface{}) error
 
 // AfterEmitHook called after event was proccessed
@@ -46,6 +97,32 @@ type Handler func(s *Store, values ...interface{}) (updatesMap map[string]interf
 
 // New initialize new store
 func New(s *Store) (*Store, error) {
+	if s.Actions != nil {
+		err := s.initActions()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := sortMiddleWares(s.MiddleWares)
+	if err != nil {
+		return nil, err
+	}
+
+	for namespace, module := range s.Modules {
+		err := s.mount(namespace, module)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.Schema != nil {
+		err := s.initSchema()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if s.OnCreate != nil {
 		for _, create := range s.OnCreate {
 			err := create(s)
@@ -59,16 +136,66 @@ func New(s *Store) (*Store, error) {
 		return nil, errors.New("store data is nil")
 	}
 
+	if s.Schema != nil {
+		err := s.checkData()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.Computed != nil {
+		err := s.initComputed()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return s, nil
 }
 
-// GetWithError return Store.Data value by query
+// GetWithError return Store.Data or Store.Computed value by query.
+// Query can be a nested path through maps, slices and exported struct fields: "user.profile.name", "todos[3].done"
 func (s *Store) GetWithError(query string) (interface{}, error) {
+	if _, ok := s.Computed[query]; ok {
+		return s.computed(query)
+	}
+
+	s.mu.RLock()
 	val, ok := s.Data[query]
-	if !ok {
+	s.mu.RUnlock()
+	if ok {
+		return val, nil
+	}
+
+	if !isPath(query) {
 		return nil, fmt.Errorf("undefined value: %s", query)
 	}
 
+	path, err := parsePath(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var root interface{}
+	if _, ok := s.Computed[path[0]]; ok {
+		root, err = s.computed(path[0])
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		s.mu.RLock()
+		root, ok = s.Data[path[0]]
+		s.mu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("undefined value: %s", query)
+		}
+	}
+
+	val, err = getPath(root, path[1:])
+	if err != nil {
+		return nil, fmt.Errorf("undefined value: %s: %s", query, err.Error())
+	}
+
 	return val, nil
 }
 
@@ -78,32 +205,121 @@ func (s *Store) Get(query string) interface{} {
 	return val
 }
 
-// Emit runs event from Store handlers
+// Values return shallow copy of Data
+func (s *Store) Values() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string]interface{}, len(s.Data))
+	for name, value := range s.Data {
+		out[name] = value
+	}
+
+	return out
+}
+
+// Emit runs event from Store handlers. Events are processed one by one,
+// so handlers and hooks mustn't call Emit synchronously (use `go s.Emit(...)`).
+// Emit is atomic: if updatesMap is invalid, subscriber updates or AfterEmit hooks failed, Data will be rolled back.
+// If event was canceled by Interceptor or BeforeEmitHook, Emit returns *CancelError
 func (s *Store) Emit(query string, values ...interface{}) error {
+	busy := s.enter(query)
+	defer s.leave(query)
+
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	e := &Event{Name: query, Values: values, busy: busy}
+	updatesMap, err := s.handle(e)
+	if err != nil || updatesMap == nil {
+		return err
+	}
+
+	return s.commit(e.Name, updatesMap, e.Values)
+}
+
+// handle run interceptors, hooks, middlewares and handler for event. Returns updatesMap with store keys.
+// Event can be changed by interceptors
+func (s *Store) handle(e *Event) (map[string]interface{}, error) {
+	for _, interceptor := range s.Interceptors {
+		if err := interceptor(s, e); err != nil {
+			return nil, &CancelError{Event: e.Name, Err: err}
+		}
+	}
+
+	query, values := e.Name, e.Values
+
+	s.mu.RLock()
 	handler, ok := s.Handlers[query]
+	module, moduleEvent := s.moduleOf(query)
+	s.mu.RUnlock()
 	if !ok {
-		return fmt.Errorf("undefined event name: %s", query)
+		return nil, fmt.Errorf("undefined event name: %s", query)
 	}
 
 	if handler == nil {
-		return fmt.Errorf("invalid handler for event: %s", query)
+		return nil, fmt.Errorf("invalid handler for event: %s", query)
 	}
 
-	if s.BeforeEmit != nil {
-		for _, beforeEmit := range s.BeforeEmit {
-			if err := beforeEmit(s, query, values); err != nil {
-				return nil
-			}
+	err := s.runBeforeEmit(s.BeforeEmit, query, query, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if module != nil {
+		err := s.runBeforeEmit(module.BeforeEmit, query, moduleEvent, values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.runMiddleWares(s.MiddleWares, query, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if module != nil {
+		err := s.runMiddleWares(module.MiddleWares, moduleEvent, values)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	for _, mw := range s.MiddleWares {
-		if !strings.HasPrefix(query, mw.Prefix) {
+	hooks := len(s.commitHooks)
+	s.current = e
+	updatesMap, err := handler(s, values...)
+	s.current = nil
+	if err != nil || updatesMap == nil {
+		s.commitHooks = s.commitHooks[:hooks] // nothing to commit
+		return nil, err
+	}
+
+	if module != nil {
+		updatesMap = module.toStoreKeys(updatesMap)
+	}
+
+	return updatesMap, nil
+}
+
+// runBeforeEmit return *CancelError if one of hooks failed
+func (s *Store) runBeforeEmit(hooks []BeforeEmitHook, query, eventName string, values []interface{}) error {
+	for _, beforeEmit := range hooks {
+		if err := beforeEmit(s, eventName, values); err != nil {
+			return &CancelError{Event: query, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) runMiddleWares(middlewares []MiddleWare, eventName string, values []interface{}) error {
+	for _, mw := range middlewares {
+		if !mw.match(eventName) {
 			continue
 		}
 
 		if mw.Hook == nil {
-			return fmt.Errorf("hook is nil in middleware with prefix '%s'", mw.Prefix)
+			return fmt.Errorf("hook is nil in middleware with %s", mw.String())
 		}
 
 		err := mw.Hook(s, values)
@@ -112,24 +328,101 @@ func (s *Store) Emit(query string, values ...interface{}) error {
 		}
 	}
 
-	updatesMap, err := handler(s, values...)
+	return nil
+}
+
+// emitted event applied to Data
+type emitted struct {
+	event      string
+	updatesMap map[string]interface{}
+	values     []interface{}
+}
+
+// commit update store and run AfterEmit hooks. emitMu must be locked
+func (s *Store) commit(eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+	keys, prev, err := s.apply(eventName, updatesMap)
 	if err != nil {
 		return err
 	}
 
-	if updatesMap == nil {
-		return nil
+	return s.publish([]emitted{{event: eventName, updatesMap: updatesMap, values: values}}, keys, prev)
+}
+
+// publish update subscribers and run AfterEmit hooks for applied events.
+// If something failed Data will be rolled back to prev
+func (s *Store) publish(events []emitted, keys []string, prev map[string]interface{}) error {
+	err := s.update(keys)
+	if err == nil {
+		err = s.runAfterEmit(events)
 	}
 
-	err = s.UpdateStore(updatesMap)
 	if err != nil {
+		s.commitHooks = nil
+		s.rollback(keys, prev)
 		return err
 	}
 
-	if s.AfterEmit != nil {
+	if s.History != nil && !s.replaying && len(events) != 0 {
+		entry := HistoryEntry{
+			Event:   events[0].event,
+			Values:  events[0].values,
+			Updates: events[0].updatesMap,
+			Prev:    prev,
+			Next:    s.fields(prev),
+		}
+
+		if len(events) > 1 {
+			entry.Event, entry.Values, entry.Updates = BatchEvent, nil, make(map[string]interface{})
+			for _, e := range events {
+				for key, value := range e.updatesMap {
+					entry.Updates[key] = value
+				}
+			}
+		}
+
+		s.History.record(entry)
+	}
+
+	s.journalRecord(events, prev)
+
+	s.runWatchers(keys)
+
+	hooks := s.commitHooks
+	s.commitHooks = nil
+	for _, hook := range hooks {
+		hook()
+	}
+
+	return nil
+}
+
+// onCommit call f after event is committed. If event fails or is rolled back f isn't called.
+// Use it in AfterEmit hooks to publish changes only when they are final. emitMu must be locked
+func (s *Store) onCommit(f func()) {
+	s.commitHooks = append(s.commitHooks, f)
+}
+
+// runAfterEmit run store and modules AfterEmit hooks
+func (s *Store) runAfterEmit(events []emitted) error {
+	for _, e := range events {
 		for _, afterEmit := range s.AfterEmit {
-			if err := afterEmit(s, query, updatesMap, values); err != nil {
-				return nil
+			if err := afterEmit(s, e.event, e.updatesMap, e.values); err != nil {
+				return err
+			}
+		}
+
+		s.mu.RLock()
+		module, moduleEvent := s.moduleOf(e.event)
+		s.mu.RUnlock()
+
+		if module == nil {
+			continue
+		}
+
+		moduleUpdates := module.toModuleKeys(e.updatesMap)
+		for _, afterEmit := range module.AfterEmit {
+			if err := afterEmit(s, moduleEvent, moduleUpdates, e.values); err != nil {
+				return err
 			}
 		}
 	}
@@ -137,36 +430,199 @@ func (s *Store) Emit(query string, values ...interface{}) error {
 	return nil
 }
 
-// UpdateStore update Store by replacing fields from updatesMap to Store.data
+// rollback restore Data fields and update subscribers
+func (s *Store) rollback(keys []string, prev map[string]interface{}) {
+	s.restore(keys, prev)
+
+	// subscribers could be partly updated, restore them too
+	s.update(keys)
+}
+
+// restore set previous values to Data fields
+func (s *Store) restore(keys []string, prev map[string]interface{}) {
+	s.mu.Lock()
+	for name, value := range prev {
+		s.Data[name] = value
+	}
+	s.mu.Unlock()
+
+	s.invalidateComputed(keys)
+}
+
+// UpdateStore update Store by replacing fields from updatesMap to Store.data.
+// updatesMap keys can be nested paths: {"user.profile.name": "Artem"}.
+// UpdateStore is serialized with events, so like Emit it mustn't be called synchronously from handlers and hooks
 func (s *Store) UpdateStore(updatesMap map[string]interface{}) error {
-	for uKey, uValue := range updatesMap {
-		oValue := s.Data[uKey]
-		if oValue == nil {
-			return fmt.Errorf("undefined field in Data: %s", uKey)
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	return s.updateStore("", updatesMap)
+}
+
+// updateStore update Store and check updatesMap fields by Schema. eventName used only in errors. emitMu must be locked
+func (s *Store) updateStore(eventName string, updatesMap map[string]interface{}) error {
+	keys, prev, err := s.apply(eventName, updatesMap)
+	if err != nil {
+		return err
+	}
+
+	return s.publish(nil, keys, prev)
+}
+
+// apply validate all updatesMap values and set them to Data.
+// Returns sorted updatesMap keys with invalidated computed names and previous values of changed Data fields
+func (s *Store) apply(eventName string, updatesMap map[string]interface{}) ([]string, map[string]interface{}, error) {
+	// parents must be updated before their childes: "user" before "user.name"
+	keys := make([]string, 0, len(updatesMap))
+	for key := range updatesMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	staged := make(map[string]interface{})
+	for _, key := range keys {
+		err := s.stageField(eventName, key, updatesMap[key], staged)
+		if err != nil {
+			s.commitHooks = nil // event won't be committed
+			return nil, nil, err
 		}
+	}
+
+	prev := make(map[string]interface{}, len(staged))
+	for name, value := range staged {
+		prev[name] = s.Data[name]
+		s.Data[name] = value
+	}
 
-		if reflect.TypeOf(uValue) != reflect.TypeOf(oValue) {
-			return fmt.Errorf("uncompared fields: %T and %T", uValue, oValue)
+	return append(keys, s.invalidateComputed(keys)...), prev, nil
+}
+
+// fields return current values of Data fields with names from names map keys
+func (s *Store) fields(names map[string]interface{}) map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string]interface{}, len(names))
+	for name := range names {
+		out[name] = s.Data[name]
+	}
+
+	return out
+}
+
+// stageField validate value and put new value of Data field to staged. s.mu must be locked
+func (s *Store) stageField(eventName, key string, value interface{}, staged map[string]interface{}) error {
+	field := func(name string) (interface{}, bool) {
+		if value, ok := staged[name]; ok {
+			return value, true
 		}
 
-		s.Data[uKey] = uValue
+		value, ok := s.Data[name]
+		return value, ok
 	}
 
-	return s.update()
+	name := key
+	if _, ok := s.Data[key]; !ok && isPath(key) {
+		path, err := parsePath(key)
+		if err != nil {
+			return &FieldError{Event: eventName, Field: key, Err: err}
+		}
+
+		name = path[0]
+		root, ok := field(name)
+		if !ok {
+			return &FieldError{Event: eventName, Field: key, Err: errors.New("undefined field in Data")}
+		}
+
+		if _, ok := s.modules[name]; ok && len(path) > 1 {
+			if err := checkModuleField(root, path[1:], value); err != nil {
+				return &FieldError{Event: eventName, Field: key, Err: err}
+			}
+		}
+
+		value, err = setPath(root, path[1:], value)
+		if err != nil {
+			return &FieldError{Event: eventName, Field: key, Err: err}
+		}
+	}
+
+	if s.Schema != nil {
+		schemaField, ok := s.Schema[name]
+		if !ok {
+			return &FieldError{Event: eventName, Field: key, Err: errors.New("undefined field in Data")}
+		}
+
+		if err := schemaField.check(value); err != nil {
+			return &FieldError{Event: eventName, Field: key, Err: err}
+		}
+
+		staged[name] = value
+		return nil
+	}
+
+	old, _ := field(name)
+	if old == nil {
+		return fmt.Errorf("undefined field in Data: %s", key)
+	}
+
+	if reflect.TypeOf(value) != reflect.TypeOf(old) {
+		return fmt.Errorf("uncompared fields: %T and %T", value, old)
+	}
+
+	staged[name] = value
+	return nil
 }
 
-// RegisterComponent register new component in store
+// RegisterComponent register new component in store. Component will be updated after every store update
 func (s *Store) RegisterComponent(c *gas.C) *gas.Component {
+	return s.register(c, nil, nil)
+}
+
+// RC alias for Store.RegisterComponent
+func (s *Store) RC(c *gas.Component) *gas.Component {
+	return s.RegisterComponent(c)
+}
+
+// RegisterComponentWithKeys register new component in store.
+// Component will be updated only if one of keys (or their nested paths) was changed
+func (s *Store) RegisterComponentWithKeys(c *gas.C, keys ...string) *gas.Component {
+	if keys == nil {
+		keys = []string{}
+	}
+
+	return s.register(c, keys, nil)
+}
+
+// RegisterComponentTracked register new component in store with root rendering by Tracker.
+// Keys component depends on are collected from Tracker.Get calls while component rendering
+func (s *Store) RegisterComponentTracked(c *gas.C, root TrackedRoot) *gas.Component {
+	tracked := &trackingRoot{root: root, s: s, c: c}
+	c.Root = tracked
+
+	return s.register(c, nil, tracked)
+}
+
+func (s *Store) register(c *gas.C, keys []string, tracked *trackingRoot) *gas.Component {
 	created := c.Hooks.Created
 	c.Hooks.Created = func() error {
-		isRoot, err := s.isRoot(c)
+		if tracked != nil {
+			keys = tracked.getKeys()
+		}
+
+		s.mu.Lock()
+		isRoot, err := s.isRoot(c, keys)
 		if err != nil {
+			s.mu.Unlock()
 			return err
 		}
 
 		if isRoot {
-			s.subs = append(s.subs, c)
+			s.subscribers = append(s.subscribers, Sub{C: c, Keys: keys})
 		}
+		s.mu.Unlock()
 
 		if created != nil {
 			err := created()
@@ -180,15 +636,7 @@ func (s *Store) RegisterComponent(c *gas.C) *gas.Component {
 
 	willDestroy := c.Hooks.BeforeDestroy
 	c.Hooks.BeforeDestroy = func() error {
-		for i, elC := range s.subs {
-			if c == elC {
-				if i >= len(s.subs) {
-					i = len(s.subs) - 1
-				}
-
-				s.subs = append(s.subs[:i], s.subs[i+1:]...)
-			}
-		}
+		s.removeSubscriber(c)
 
 		if willDestroy != nil {
 			err := willDestroy()
@@ -203,39 +651,54 @@ func (s *Store) RegisterComponent(c *gas.C) *gas.Component {
 	return c
 }
 
-// RC alias for Store.RegisterComponent
-func (s *Store) RC(c *gas.Component) *gas.Component {
-	return s.RegisterComponent(c)
-}
+// isRoot check if component have no RegisteredComponents which will update him after store updates.
+// Components with keys always are root, because their parents can skip updates they need
+func (s *Store) isRoot(c *gas.Component, keys []string) (bool, error) {
+	if keys != nil {
+		return true, nil
+	}
 
-// isRoot check if component have no RegisteredComponents which will update him after store updates
-func (s *Store) isRoot(c *gas.Component) (bool, error) {
 	if c.Element.Parent == nil { // it's root element
 		return true, nil
 	}
 
 	parent := c.Element.ParentComponent()
-	if parent == nil {
+	if parent == nil || parent.Component == nil {
 		return true, nil
 	}
 
-	for _, sub := range s.subs {
-		changed, _, err := gas.Changed(sub, parent)
-		if err != nil {
-			return false, err
-		}
-
-		if !changed {
+	for _, sub := range s.subscribers {
+		if sub.Keys == nil && sub.C == parent.Component {
 			return false, nil
 		}
 	}
 
-	return s.isRoot(parent.Component)
+	return s.isRoot(parent.Component, keys)
 }
 
-// update run ForceUpdate for all subs
-func (s *Store) update() error {
-	for _, sub := range s.subs {
+// update run UpdateWithError for all subscribers depending on changed keys
+func (s *Store) update(changed []string) error {
+	var subs []*gas.Component
+	s.mu.RLock()
+	for _, sub := range s.subscribers {
+		if sub.Keys == nil || keysOverlap(sub.Keys, changed) {
+			subs = append(subs, sub.C)
+		}
+	}
+	bindings := append([]*Binding{}, s.bindings...)
+	s.mu.RUnlock()
+
+	for _, b := range bindings {
+		if b.refresh(changed) && !containsComponent(subs, b.c) {
+			subs = append(subs, b.c)
+		}
+	}
+
+	for _, sub := range subs {
+		if hasParentIn(sub, subs) { // will be updated with parent
+			continue
+		}
+
 		if sub.Element.BEElement() == nil {
 			return errors.New("element undefined")
 		}
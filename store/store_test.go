@@ -3,12 +3,14 @@ package store
 import (
 	"errors"
 	"fmt"
-	"github.com/gascore/gas"
 	"testing"
+
+	"github.com/gascore/gas"
+	"github.com/gascore/std/store/internal/fake"
 )
 
 func TestEz(t *testing.T) {
-	fmt.Println("ez")	
+	fmt.Println("ez")
 }
 
 func TestNew(t *testing.T) {
@@ -37,8 +39,8 @@ func TestNew(t *testing.T) {
 
 				counter := s.Get("counter").(int)
 				return map[string]interface{}{
-					"counter": counter+value,
-				},nil
+					"counter": counter + value,
+				}, nil
 			},
 		},
 
@@ -52,7 +54,7 @@ func TestNew(t *testing.T) {
 			},
 		},
 
-		OnCreate:[]OnCreateHook{
+		OnCreate: []OnCreateHook{
 			func(s *Store) error {
 				onCreateWasCalled = true
 				return nil
@@ -85,32 +87,16 @@ func TestNew(t *testing.T) {
 	}
 
 	// Add
-	registeredComponent := s.RegisterComponent(gas.NC(
-		&gas.C{
-			Tag: "p",
-			Attrs: func() gas.Map {
-				return gas.Map{
-					"class": "component",
-				}
-			},
-			RC: gas.GetEmptyRenderCore(),
-		},
-		func(this *gas.Component) []interface{} {
-			return []interface{}{
-				s.Get("counter"),
-			}
-		},
-	), nil)
+	c := fake.NewComponent(func() []interface{} {
+		return []interface{}{s.Get("counter")}
+	})
+	registeredComponent := s.RegisterComponent(c.C)
 	if registeredComponent == nil {
 		t.Errorf("store RegisterComponent result is nil")
 	}
 
 	// Mounted
-	err = gas.CallMounted(registeredComponent)
-	if err != nil {
-		t.Errorf("unexpected error in RunMounted: %s", err.Error())
-		return
-	}
+	mountComponent(t, registeredComponent)
 
 	if len(s.subscribers) == 0 {
 		t.Error("component was not added to store subscribers")
@@ -139,8 +125,13 @@ func TestNew(t *testing.T) {
 		return
 	}
 
+	if c.Renders() != 2 {
+		t.Errorf("component wasn't updated, renders count: %d", c.Renders())
+		return
+	}
+
 	// BeforeDestroy
-	err = gas.CallBeforeDestroy(registeredComponent)
+	err = gas.CallBeforeDestroy(registeredComponent.Element)
 	if err != nil {
 		t.Errorf("unexpected error in RunWillDestroy: %s", err.Error())
 		return
@@ -152,6 +143,13 @@ func TestNew(t *testing.T) {
 	}
 }
 
+// componentWith create component rendered to el
+func componentWith(el *gas.E) *gas.C {
+	c := &gas.C{Root: &gas.EmptyRoot{Element: el}, Element: el}
+	el.Component = c
+	return c
+}
+
 func TestIsRoot(t *testing.T) {
 	s, err := New(&Store{
 		Data: map[string]interface{}{
@@ -163,78 +161,59 @@ func TestIsRoot(t *testing.T) {
 		return
 	}
 
-	c := gas.NC(
-		&gas.C{
-			Tag: "p",
-			Attrs: func() gas.Map {
-				return gas.Map{
-					"class": "component",
-				}
-			},
-		},
-		func(this *gas.Component) []interface{} {
-			return []interface{}{
-				"component",
-			}
-		},
-	)
-
-	var emptyChildes gas.GetComponentChildes
-
-	componentInStore := gas.NC(
-		&gas.C{
-			Tag: "div",
-		}, emptyChildes)
-
+	componentInStore := componentWith(&gas.E{Tag: "div"})
 	s.subscribers = append(s.subscribers, Sub{componentInStore, nil})
 
-	data := []struct{
-		parent *gas.Component
+	data := []struct {
+		parent *gas.Element
+		keys   []string
 		isRoot bool
 	}{
 		{
-			parent: gas.NC(
-				&gas.C{
-					Tag: "h1",
-					Parent: gas.NC(
-						&gas.C{
-							Tag: "div",
-						}, emptyChildes),
-				}, emptyChildes,
-			),
+			parent: &gas.E{
+				Tag:    "h1",
+				Parent: &gas.E{Tag: "div"},
+			},
 			isRoot: true,
 		},
 		{
-			parent: gas.NC(
-				&gas.C{
-					Tag: "h1",
-					Parent: gas.NC(
-						&gas.C{
-							Tag: "div",
-							Parent: componentInStore,
-						}, emptyChildes),
-				}, emptyChildes,
-			),
+			parent: componentWith(&gas.E{
+				Tag: "h1",
+				Parent: &gas.E{
+					Tag:    "div",
+					Parent: componentInStore.Element,
+				},
+			}).Element,
 			isRoot: false,
 		},
 		{
-			parent: gas.NE(
-				&gas.C{
-					Tag: "h1",
-					Parent: gas.NC(
-						&gas.C{
-							Tag: "div",
-							Parent: componentInStore,
-						}, emptyChildes),
+			parent: &gas.E{
+				Tag: "h1",
+				Parent: &gas.E{
+					Tag:    "div",
+					Parent: componentInStore.Element,
 				},
-			),
+			},
 			isRoot: false,
 		},
+		{
+			parent: &gas.E{
+				Tag:    "h1",
+				Parent: componentInStore.Element,
+			},
+			keys:   []string{"counter"},
+			isRoot: true,
+		},
 	}
 
 	for _, el := range data {
-		c.Parent = el.parent
-		isRoot := s.isRoot(c)
+		c := componentWith(&gas.E{Tag: "p", Parent: el.parent})
+		isRoot, err := s.isRoot(c, el.keys)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			continue
+		}
+
 		if isRoot != el.isRoot {
 			t.Errorf("invalid isRoot result want: %t, got: %t", el.isRoot, isRoot)
 		}
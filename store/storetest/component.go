@@ -0,0 +1,20 @@
+package storetest
+
+import (
+	"github.com/gascore/gas"
+	"github.com/gascore/std/store/internal/fake"
+)
+
+// Component fake store subscriber rendered by empty render core.
+// Register Component.C in store before Mount
+type Component = fake.Component
+
+// NewComponent create fake component. render returns component childes, it can be nil
+func NewComponent(render func() []interface{}) *Component {
+	return fake.NewComponent(render)
+}
+
+// Wrap create plain element (not component) inside parent
+func Wrap(parent *gas.Element, tag string) *gas.Element {
+	return &gas.E{Tag: tag, Parent: parent}
+}
@@ -0,0 +1,197 @@
+// Package storetest provides helpers for testing stores and store subscribers without browser
+package storetest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gascore/std/store"
+)
+
+// Event event recorded by Recorder
+type Event struct {
+	Name    string
+	Values  []interface{}
+	Updates map[string]interface{}
+}
+
+// Recorder record events committed by Store. Use Recorder.AfterEmit in Store.AfterEmit or create store by NewStore
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewStore create store with Recorder as last AfterEmit hook
+func NewStore(s *store.Store) (*store.Store, *Recorder, error) {
+	r := &Recorder{}
+	s.AfterEmit = append(s.AfterEmit, r.AfterEmit)
+
+	s, err := store.New(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, r, nil
+}
+
+// AfterEmit record event
+func (r *Recorder) AfterEmit(s *store.Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, Event{Name: eventName, Values: values, Updates: updatesMap})
+	return nil
+}
+
+// Events return recorded events
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]Event, len(r.events))
+	copy(out, r.events)
+
+	return out
+}
+
+// Names return names of recorded events
+func (r *Recorder) Names() []string {
+	events := r.Events()
+
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = e.Name
+	}
+
+	return names
+}
+
+// Changed return sorted keys changed by recorded events
+func (r *Recorder) Changed() []string {
+	set := make(map[string]bool)
+	for _, e := range r.Events() {
+		for key := range e.Updates {
+			set[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Reset forget recorded events
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = nil
+}
+
+// AssertEvents check names of recorded events
+func (r *Recorder) AssertEvents(t testing.TB, names ...string) bool {
+	t.Helper()
+
+	got := r.Names()
+	if strings.Join(got, ",") != strings.Join(names, ",") {
+		t.Errorf("invalid events want: %v, got: %v", names, got)
+		return false
+	}
+
+	return true
+}
+
+// AssertChanged check keys changed by recorded events
+func (r *Recorder) AssertChanged(t testing.TB, keys ...string) bool {
+	t.Helper()
+
+	want := append([]string{}, keys...)
+	sort.Strings(want)
+
+	got := r.Changed()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("invalid changed keys want: %v, got: %v", want, got)
+		return false
+	}
+
+	return true
+}
+
+// AssertNotChanged check that recorded events didn't change keys
+func (r *Recorder) AssertNotChanged(t testing.TB, keys ...string) bool {
+	t.Helper()
+
+	ok := true
+	for _, changed := range r.Changed() {
+		for _, key := range keys {
+			if changed == key {
+				t.Errorf("key '%s' was changed", key)
+				ok = false
+			}
+		}
+	}
+
+	return ok
+}
+
+// Snapshot return copy of store Data fields
+func Snapshot(s *store.Store) map[string]interface{} {
+	return s.Values()
+}
+
+// AssertData check that store fields are equal to want fields. Fields not in want aren't checked
+func AssertData(t testing.TB, s *store.Store, want map[string]interface{}) bool {
+	t.Helper()
+
+	got := s.Values()
+
+	names := make([]string, 0, len(want))
+	for name := range want {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ok := true
+	for _, name := range names {
+		value, exists := got[name]
+		if !exists {
+			t.Errorf("field '%s' is undefined", name)
+			ok = false
+			continue
+		}
+
+		if !reflect.DeepEqual(value, want[name]) {
+			t.Errorf("invalid field '%s' want: %s, got: %s", name, format(want[name]), format(value))
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// AssertSnapshot check that store Data is equal to snapshot
+func AssertSnapshot(t testing.TB, s *store.Store, snapshot map[string]interface{}) bool {
+	t.Helper()
+
+	ok := AssertData(t, s, snapshot)
+	for name := range s.Values() {
+		if _, exists := snapshot[name]; !exists {
+			t.Errorf("field '%s' isn't in snapshot", name)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+func format(value interface{}) string {
+	return fmt.Sprintf("%#v", value)
+}
@@ -0,0 +1,94 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/gascore/std/store"
+)
+
+func newStore(t *testing.T) (*store.Store, *Recorder) {
+	s, r, err := NewStore(&store.Store{
+		Data: map[string]interface{}{
+			"counter": 0,
+			"title":   "",
+		},
+		Handlers: map[string]store.Handler{
+			"inc": func(s *store.Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": s.Get("counter").(int) + 1}, nil
+			},
+			"rename": func(s *store.Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"title": values[0]}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return s, r
+}
+
+func TestRecorder(t *testing.T) {
+	s, r := newStore(t)
+
+	s.Emit("inc")
+	s.Emit("inc")
+
+	r.AssertEvents(t, "inc", "inc")
+	r.AssertChanged(t, "counter")
+	r.AssertNotChanged(t, "title")
+	AssertData(t, s, map[string]interface{}{"counter": 2})
+
+	snapshot := Snapshot(s)
+	r.Reset()
+
+	s.Emit("rename", "hello")
+	r.AssertEvents(t, "rename")
+
+	snapshot["title"] = "hello"
+	AssertSnapshot(t, s, snapshot)
+}
+
+func TestSubscribers(t *testing.T) {
+	s, _ := newStore(t)
+
+	parent := NewComponent(nil)
+	s.RegisterComponent(parent.C)
+	if err := parent.Mount(); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	// child of registered component isn't subscriber, it's updated with parent
+	child := NewComponent(nil)
+	s.RegisterComponent(child.C)
+	if err := child.MountIn(Wrap(parent.Element(), "div")); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	// components with keys are always subscribers
+	keyed := NewComponent(func() []interface{} { return []interface{}{s.Get("title")} })
+	s.RegisterComponentWithKeys(keyed.C, "title")
+	if err := keyed.MountIn(parent.Element()); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if subs := s.Subscribers(); len(subs) != 2 || subs[0].C != parent.C || subs[1].C != keyed.C {
+		t.Errorf("invalid subscribers: %v", subs)
+		return
+	}
+
+	s.Emit("inc")
+	parent.AssertRenders(t, 2)
+	keyed.AssertRenders(t, 1)
+
+	if err := keyed.Destroy(); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	if len(s.Subscribers()) != 1 {
+		t.Error("component wasn't removed from subscribers")
+	}
+}
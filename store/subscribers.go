@@ -0,0 +1,160 @@
+package store
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gascore/gas"
+)
+
+// Sub store subscriber
+type Sub struct {
+	C    *gas.Component
+	Keys []string // Data keys component depends on. If Keys is nil component depends on all keys
+}
+
+// Tracker Store getter collecting keys read by one render of tracked component or one selector call
+type Tracker struct {
+	s    *Store
+	keys []string
+}
+
+// Store return tracked store
+func (t *Tracker) Store() *Store {
+	return t.s
+}
+
+// Get Store.Get remembering query
+func (t *Tracker) Get(query string) interface{} {
+	val, _ := t.GetWithError(query)
+	return val
+}
+
+// GetWithError Store.GetWithError remembering query
+func (t *Tracker) GetWithError(query string) (interface{}, error) {
+	t.keys = append(t.keys, query)
+	return t.s.GetWithError(query)
+}
+
+// TrackedRoot root of component registered by RegisterComponentTracked
+type TrackedRoot interface {
+	Render(t *Tracker) *gas.Element
+}
+
+// trackingRoot collect keys component reads from store while rendering
+type trackingRoot struct {
+	root TrackedRoot
+
+	s *Store
+	c *gas.Component
+
+	mu   sync.Mutex
+	keys []string
+}
+
+func (root *trackingRoot) Render() *gas.Element {
+	var el *gas.Element
+	keys := root.s.track(func(t *Tracker) {
+		el = root.root.Render(t)
+	})
+
+	root.mu.Lock()
+	root.keys = keys
+	root.mu.Unlock()
+
+	root.s.setSubscriberKeys(root.c, keys)
+
+	return el
+}
+
+// track return keys read by fn from its own Tracker
+func (s *Store) track(fn func(t *Tracker)) []string {
+	t := &Tracker{s: s, keys: []string{}}
+	fn(t)
+
+	return t.keys
+}
+
+func (root *trackingRoot) getKeys() []string {
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	return root.keys
+}
+
+// Subscribers return registered components
+func (s *Store) Subscribers() []Sub {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Sub, len(s.subscribers))
+	copy(out, s.subscribers)
+
+	return out
+}
+
+// setSubscriberKeys replace keys of registered component
+func (s *Store) setSubscriberKeys(c *gas.Component, keys []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, sub := range s.subscribers {
+		if sub.C == c {
+			s.subscribers[i].Keys = keys
+		}
+	}
+}
+
+// removeSubscriber remove component from store subscribers
+func (s *Store) removeSubscriber(c *gas.Component) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, sub := range s.subscribers {
+		if sub.C == c {
+			s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// keysOverlap return true if one of keys depends on one of changed keys
+func keysOverlap(keys, changed []string) bool {
+	for _, key := range keys {
+		for _, changedKey := range changed {
+			if keyDependsOn(key, changedKey) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// keyDependsOn return true if a and b are equal or one of them is nested path of another: "user" and "user.name"
+func keyDependsOn(a, b string) bool {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(a, b+"[")
+}
+
+// hasParentIn return true if one of component parents is in components list
+func hasParentIn(c *gas.Component, components []*gas.Component) bool {
+	if c.Element == nil {
+		return false
+	}
+
+	for parent := c.Element.Parent; parent != nil; parent = parent.Parent {
+		if parent.Component == nil {
+			continue
+		}
+
+		if containsComponent(components, parent.Component) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/gascore/gas"
+	"github.com/gascore/std/store/internal/fake"
+)
+
+// trackedCountingRoot fake.Component root rendering by Tracker
+type trackedCountingRoot struct {
+	renders int
+	render  func(t *Tracker) []interface{}
+}
+
+func (root *trackedCountingRoot) Render(t *Tracker) *gas.Element {
+	root.renders++
+	return gas.NE(&gas.E{}, root.render(t)...)
+}
+
+// mountComponent render component and call Created hook as gas does
+func mountComponent(t *testing.T, c *gas.Component) {
+	err := c.UpdateWithError()
+	if err != nil {
+		t.Errorf("unexpected error in first render: %s", err.Error())
+	}
+
+	err = c.Hooks.Created()
+	if err != nil {
+		t.Errorf("unexpected error in Created: %s", err.Error())
+	}
+}
+
+func TestKeyDependsOn(t *testing.T) {
+	data := []struct {
+		a, b    string
+		depends bool
+	}{
+		{a: "user", b: "user", depends: true},
+		{a: "user", b: "user.name", depends: true},
+		{a: "todos[1].done", b: "todos", depends: true},
+		{a: "user", b: "username", depends: false},
+		{a: "user.name", b: "user.age", depends: false},
+	}
+
+	for _, el := range data {
+		if keyDependsOn(el.a, el.b) != el.depends {
+			t.Errorf("invalid keyDependsOn result for '%s' and '%s' want: %t", el.a, el.b, el.depends)
+		}
+	}
+}
+
+func TestFineGrainedSubscriptions(t *testing.T) {
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"counter": 0,
+			"user":    map[string]string{"name": "Artem"},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	all := fake.NewComponent(nil)
+	s.RegisterComponent(all.C)
+
+	withKeys := fake.NewComponent(nil)
+	s.RegisterComponentWithKeys(withKeys.C, "user")
+
+	trackedRoot := &trackedCountingRoot{render: func(t *Tracker) []interface{} {
+		s.Get("user") // reads not by Tracker (from other goroutines too) aren't tracked
+		return []interface{}{t.Get("counter")}
+	}}
+	tracked := s.RegisterComponentTracked(&gas.C{RC: gas.GetEmptyRenderCore()}, trackedRoot)
+
+	for _, c := range []*gas.Component{all.C, withKeys.C, tracked} {
+		mountComponent(t, c)
+	}
+
+	if len(s.subscribers) != 3 {
+		t.Errorf("invalid subscribers count want: 3, got: %d", len(s.subscribers))
+		return
+	}
+
+	data := []struct {
+		updates                map[string]interface{}
+		all, withKeys, tracked int
+	}{
+		{updates: map[string]interface{}{"counter": 1}, all: 2, withKeys: 1, tracked: 2},
+		{updates: map[string]interface{}{"user.name": "Elen"}, all: 3, withKeys: 2, tracked: 2},
+		{updates: map[string]interface{}{"user": map[string]string{}}, all: 4, withKeys: 3, tracked: 2},
+	}
+
+	for _, el := range data {
+		err := s.UpdateStore(el.updates)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+
+		if all.Renders() != el.all || withKeys.Renders() != el.withKeys || trackedRoot.renders != el.tracked {
+			t.Errorf("invalid renders count after %v want: %d %d %d, got: %d %d %d", el.updates,
+				el.all, el.withKeys, el.tracked,
+				all.Renders(), withKeys.Renders(), trackedRoot.renders)
+		}
+	}
+
+	err = withKeys.Destroy()
+	if err != nil {
+		t.Errorf("unexpected error in BeforeDestroy: %s", err.Error())
+		return
+	}
+
+	if len(s.subscribers) != 2 {
+		t.Errorf("component was not removed from store subscribers")
+	}
+}
@@ -0,0 +1,424 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/frankenbeanies/uuid4"
+)
+
+// SyncEvent event name for updates received from other stores
+const SyncEvent = "store/sync"
+
+// Channel transport between synchronized stores (browser tabs). Channel mustn't deliver messages to sender
+type Channel interface {
+	Post(msg []byte) error
+	Listen(handler func(msg []byte)) (stop func())
+}
+
+// SyncMessage message with committed fields sent by Sync
+type SyncMessage struct {
+	Source string                     `json:"source"`
+	Event  string                     `json:"event"`
+	Clock  uint64                     `json:"clock"` // sender logical clock, orders changes of all stores
+	Time   int64                      `json:"time"`  // unix nanoseconds by sender Store.Clock
+	Fields map[string]json.RawMessage `json:"fields"`
+	Prev   map[string]json.RawMessage `json:"prev,omitempty"` // fields values sender had synced before event
+}
+
+// Conflict remote update of field which was changed locally since last synchronization.
+// Clocks are logical: change seen by store before its own change always has lower clock
+type Conflict struct {
+	Field string
+
+	Local       interface{}
+	LocalClock  uint64
+	LocalTime   time.Time
+	LocalSource string // id of store which made local value
+
+	Remote      interface{}
+	RemoteClock uint64
+	RemoteTime  time.Time
+	Source      string // remote store id
+}
+
+// ConflictHandler return value field must have after conflict
+type ConflictHandler func(s *Store, c Conflict) (interface{}, error)
+
+// LastWriteWins ConflictHandler choosing the latest value by logical clock.
+// Concurrent changes with equal clocks are ordered by stores ids, so all stores choose the same value
+func LastWriteWins(s *Store, c Conflict) (interface{}, error) {
+	if c.RemoteClock != c.LocalClock {
+		if c.RemoteClock > c.LocalClock {
+			return c.Remote, nil
+		}
+
+		return c.Local, nil
+	}
+
+	if c.Source > c.LocalSource {
+		return c.Remote, nil
+	}
+
+	return c.Local, nil
+}
+
+// RemoteWins ConflictHandler always choosing remote value
+func RemoteWins(s *Store, c Conflict) (interface{}, error) {
+	return c.Remote, nil
+}
+
+// LocalWins ConflictHandler always choosing local value
+func LocalWins(s *Store, c Conflict) (interface{}, error) {
+	return c.Local, nil
+}
+
+// Sync synchronizing Store.Data fields with other stores through Channel.
+// Committed fields are sent as JSON, received fields are decoded like in Persist.
+//
+// Use Sync.OnCreate in Store.OnCreate (after Persist.OnCreate) and Sync.AfterEmit in Store.AfterEmit:
+//
+// sy := &store.Sync{Channel: store.BroadcastChannel("app")}
+// s, err := store.New(&store.Store{..., OnCreate: []store.OnCreateHook{sy.OnCreate}, AfterEmit: []store.AfterEmitHook{sy.AfterEmit}})
+type Sync struct {
+	Channel Channel
+	ID      string // store id, random UUID if empty
+
+	Whitelist []string // sync only these fields
+	Blacklist []string // don't sync these fields
+
+	Conflict ConflictHandler // LastWriteWins if nil
+
+	OnError func(err error) // called for errors in received messages
+
+	mu       sync.Mutex
+	clock    uint64                     // logical clock
+	synced   map[string]json.RawMessage // last synchronized fields values
+	versions map[string]syncVersion     // last fields changes
+	stop     func()
+}
+
+// syncVersion field change
+type syncVersion struct {
+	clock  uint64
+	time   time.Time
+	source string
+}
+
+// OnCreate remember initial Data and start listening Channel
+func (sy *Sync) OnCreate(s *Store) error {
+	if sy.Channel == nil {
+		return errors.New("sync channel is nil")
+	}
+
+	if len(sy.ID) == 0 {
+		sy.ID = uuid4.New().String()
+	}
+
+	sy.synced = make(map[string]json.RawMessage)
+	sy.versions = make(map[string]syncVersion)
+	for name, value := range s.Data {
+		if !allowedField(name, sy.Whitelist, sy.Blacklist) {
+			continue
+		}
+
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+
+		sy.synced[name] = raw
+	}
+
+	sy.stop = sy.Channel.Listen(func(msg []byte) {
+		if err := sy.receive(s, msg); err != nil && sy.OnError != nil {
+			sy.OnError(err)
+		}
+	})
+
+	return nil
+}
+
+// AfterEmit send changed fields to Channel after event is committed. Rolled back events aren't sent
+func (sy *Sync) AfterEmit(s *Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+	if eventName == SyncEvent {
+		return nil
+	}
+
+	fields := make(map[string]json.RawMessage)
+	for key := range updatesMap {
+		name := fieldName(key)
+		if _, ok := fields[name]; ok || !allowedField(name, sy.Whitelist, sy.Blacklist) {
+			continue
+		}
+
+		s.mu.RLock()
+		raw, err := json.Marshal(s.Data[name])
+		s.mu.RUnlock()
+		if err != nil {
+			return &FieldError{Event: eventName, Field: name, Err: err}
+		}
+
+		fields[name] = raw
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	s.onCommit(func() {
+		if err := sy.post(s, eventName, fields); err != nil && sy.OnError != nil {
+			sy.OnError(err)
+		}
+	})
+
+	return nil
+}
+
+// post send committed fields to Channel
+func (sy *Sync) post(s *Store, eventName string, fields map[string]json.RawMessage) error {
+	sy.mu.Lock()
+	sy.clock++
+	version := syncVersion{clock: sy.clock, time: s.clock().Now(), source: sy.ID}
+
+	msg := SyncMessage{
+		Source: sy.ID,
+		Event:  eventName,
+		Clock:  version.clock,
+		Time:   version.time.UnixNano(),
+		Fields: fields,
+		Prev:   make(map[string]json.RawMessage),
+	}
+
+	for name, raw := range fields {
+		if prev, ok := sy.synced[name]; ok {
+			msg.Prev[name] = prev
+		}
+
+		sy.synced[name] = raw
+		sy.versions[name] = version
+	}
+	sy.mu.Unlock()
+
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return sy.Channel.Post(raw)
+}
+
+// Close stop listening Channel
+func (sy *Sync) Close() {
+	if sy.stop != nil {
+		sy.stop()
+	}
+}
+
+// receive apply fields from other store
+func (sy *Sync) receive(s *Store, raw []byte) error {
+	var msg SyncMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return err
+	}
+
+	if msg.Source == sy.ID {
+		return nil
+	}
+
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	sy.mu.Lock()
+	defer sy.mu.Unlock()
+
+	if msg.Clock > sy.clock {
+		sy.clock = msg.Clock
+	}
+
+	remoteVersion := syncVersion{clock: msg.Clock, time: time.Unix(0, msg.Time), source: msg.Source}
+
+	names := make([]string, 0, len(msg.Fields))
+	for name := range msg.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	updatesMap := make(map[string]interface{})
+	for _, name := range names {
+		if !allowedField(name, sy.Whitelist, sy.Blacklist) {
+			continue
+		}
+
+		s.mu.RLock()
+		local, ok := s.Data[name]
+		typ := s.fieldType(name)
+		s.mu.RUnlock()
+
+		if !ok {
+			continue
+		}
+
+		remoteRaw := msg.Fields[name]
+		remote, err := decodeField(string(remoteRaw), typ)
+		if err != nil {
+			return &FieldError{Event: SyncEvent, Field: name, Err: err}
+		}
+
+		localRaw, err := json.Marshal(local)
+		if err != nil {
+			return &FieldError{Event: SyncEvent, Field: name, Err: err}
+		}
+
+		if string(localRaw) == string(remoteRaw) {
+			sy.synced[name] = remoteRaw
+			continue
+		}
+
+		value := remote
+		if prev, ok := msg.Prev[name]; !ok || string(prev) != string(localRaw) {
+			handler := sy.Conflict
+			if handler == nil {
+				handler = LastWriteWins
+			}
+
+			localVersion := sy.versions[name]
+			value, err = handler(s, Conflict{
+				Field:       name,
+				Local:       local,
+				LocalClock:  localVersion.clock,
+				LocalTime:   localVersion.time,
+				LocalSource: localVersion.source,
+				Remote:      remote,
+				RemoteClock: remoteVersion.clock,
+				RemoteTime:  remoteVersion.time,
+				Source:      msg.Source,
+			})
+			if err != nil {
+				return &FieldError{Event: SyncEvent, Field: name, Err: err}
+			}
+		}
+
+		if reflect.DeepEqual(value, local) {
+			continue
+		}
+
+		valueRaw, err := json.Marshal(value)
+		if err != nil {
+			return &FieldError{Event: SyncEvent, Field: name, Err: err}
+		}
+
+		updatesMap[name] = value
+		sy.synced[name] = valueRaw
+		if remoteVersion.newer(sy.versions[name]) {
+			sy.versions[name] = remoteVersion
+		}
+	}
+
+	if len(updatesMap) == 0 {
+		return nil
+	}
+
+	return s.commit(SyncEvent, updatesMap, []interface{}{msg.Source, msg.Event})
+}
+
+// newer return true if v is after other by clock and store id
+func (v syncVersion) newer(other syncVersion) bool {
+	if v.clock != other.clock {
+		return v.clock > other.clock
+	}
+
+	return v.source > other.source
+}
+
+// MemoryBus in-memory Channel hub for tests. Messages are queued until Deliver
+type MemoryBus struct {
+	mu       sync.Mutex
+	queue    []busMessage
+	handlers map[int]func(msg []byte)
+	nextID   int
+}
+
+type busMessage struct {
+	from int
+	msg  []byte
+}
+
+// NewMemoryBus create empty MemoryBus
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{handlers: make(map[int]func(msg []byte))}
+}
+
+// Channel create new bus member
+func (b *MemoryBus) Channel() Channel {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	return &memoryChannel{bus: b, id: b.nextID}
+}
+
+// Deliver send queued messages to all members except senders. Return count of delivered messages
+func (b *MemoryBus) Deliver() int {
+	count := 0
+	for {
+		b.mu.Lock()
+		if len(b.queue) == 0 {
+			b.mu.Unlock()
+			return count
+		}
+
+		m := b.queue[0]
+		b.queue = b.queue[1:]
+
+		ids := make([]int, 0, len(b.handlers))
+		for id := range b.handlers {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+
+		var handlers []func(msg []byte)
+		for _, id := range ids {
+			if id != m.from {
+				handlers = append(handlers, b.handlers[id])
+			}
+		}
+		b.mu.Unlock()
+
+		for _, handler := range handlers {
+			handler(m.msg)
+		}
+
+		count++
+	}
+}
+
+type memoryChannel struct {
+	bus *MemoryBus
+	id  int
+}
+
+func (c *memoryChannel) Post(msg []byte) error {
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+
+	c.bus.queue = append(c.bus.queue, busMessage{from: c.id, msg: msg})
+	return nil
+}
+
+func (c *memoryChannel) Listen(handler func(msg []byte)) func() {
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+
+	c.bus.handlers[c.id] = handler
+	return func() {
+		c.bus.mu.Lock()
+		defer c.bus.mu.Unlock()
+
+		delete(c.bus.handlers, c.id)
+	}
+}
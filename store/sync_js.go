@@ -0,0 +1,97 @@
+//go:build js && wasm
+// +build js,wasm
+
+package store
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"syscall/js"
+	"time"
+
+	"github.com/gascore/dom/storage"
+)
+
+// BroadcastChannel return Channel using BroadcastChannel API.
+// If BroadcastChannel isn't supported StorageChannel will be used
+func BroadcastChannel(name string) Channel {
+	ctor := js.Global().Get("BroadcastChannel")
+	if !ctor.Truthy() {
+		return StorageChannel(name)
+	}
+
+	return &broadcastChannel{ch: ctor.New(name)}
+}
+
+type broadcastChannel struct {
+	ch js.Value
+}
+
+func (b *broadcastChannel) Post(msg []byte) error {
+	b.ch.Call("postMessage", string(msg))
+	return nil
+}
+
+func (b *broadcastChannel) Listen(handler func(msg []byte)) func() {
+	onMessage := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		msg := []byte(args[0].Get("data").String())
+		go handler(msg) // handler can block, js callbacks mustn't
+		return nil
+	})
+
+	b.ch.Call("addEventListener", "message", onMessage)
+	return func() {
+		b.ch.Call("removeEventListener", "message", onMessage)
+		onMessage.Release()
+	}
+}
+
+// StorageChannel return Channel using localStorage key and window "storage" event
+func StorageChannel(key string) Channel {
+	return &storageChannel{key: key}
+}
+
+type storageChannel struct {
+	key string
+}
+
+func (c *storageChannel) Post(msg []byte) error {
+	st := storage.Local()
+	if st == nil {
+		return errors.New("localStorage is unavailable")
+	}
+
+	// storage event is fired only if value was changed, so every message has unique prefix
+	st.SetItem(c.key, strconv.FormatInt(time.Now().UnixNano(), 36)+" "+string(msg))
+	return nil
+}
+
+func (c *storageChannel) Listen(handler func(msg []byte)) func() {
+	window := js.Global()
+
+	onStorage := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		event := args[0]
+		if event.Get("key").String() != c.key {
+			return nil
+		}
+
+		value := event.Get("newValue")
+		if value.Type() != js.TypeString {
+			return nil
+		}
+
+		raw := value.String()
+		if i := strings.IndexByte(raw, ' '); i != -1 {
+			go handler([]byte(raw[i+1:]))
+		}
+
+		return nil
+	})
+
+	window.Call("addEventListener", "storage", onStorage)
+	return func() {
+		window.Call("removeEventListener", "storage", onStorage)
+		onStorage.Release()
+	}
+}
@@ -0,0 +1,227 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gascore/std/store/internal/fake"
+)
+
+func newSyncStore(sy *Sync) (*Store, error) {
+	return New(&Store{
+		Data: map[string]interface{}{
+			"counter": 0,
+			"title":   "",
+			"local":   "",
+		},
+		Handlers: map[string]Handler{
+			"set": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{values[0].(string): values[1]}, nil
+			},
+		},
+		OnCreate:  []OnCreateHook{sy.OnCreate},
+		AfterEmit: []AfterEmitHook{sy.AfterEmit},
+	})
+}
+
+func TestSync(t *testing.T) {
+	bus := NewMemoryBus()
+
+	syncA := &Sync{Channel: bus.Channel(), ID: "a", Blacklist: []string{"local"}}
+	a, err := newSyncStore(syncA)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	syncB := &Sync{Channel: bus.Channel(), ID: "b", Blacklist: []string{"local"}}
+	b, err := newSyncStore(syncB)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	c := fake.NewComponent(nil)
+	b.RegisterComponentWithKeys(c.C, "counter")
+	mountComponent(t, c.C)
+
+	a.Emit("set", "counter", 5)
+	a.Emit("set", "local", "only a")
+
+	if count := bus.Deliver(); count != 1 {
+		t.Errorf("invalid delivered messages count want: 1, got: %d", count)
+	}
+
+	if b.Get("counter") != 5 {
+		t.Errorf("counter wasn't synced: %#v", b.Get("counter"))
+	}
+
+	if b.Get("local") != "" {
+		t.Errorf("blacklisted field was synced: %#v", b.Get("local"))
+	}
+
+	if c.Renders() != 2 {
+		t.Errorf("invalid renders count want: 2, got: %d", c.Renders())
+	}
+
+	// concurrent changes have equal clocks: store with greater id wins in both stores
+	a.Emit("set", "title", "from a")
+	b.Emit("set", "title", "from b")
+	bus.Deliver()
+
+	if a.Get("title") != "from b" || b.Get("title") != "from b" {
+		t.Errorf("stores diverged: a: %v, b: %v", a.Get("title"), b.Get("title"))
+	}
+
+	// change seen before own change is older
+	b.Emit("set", "title", "from b again")
+	bus.Deliver()
+	a.Emit("set", "title", "from a again")
+	bus.Deliver()
+
+	if a.Get("title") != "from a again" || b.Get("title") != "from a again" {
+		t.Errorf("later change was lost: a: %v, b: %v", a.Get("title"), b.Get("title"))
+	}
+
+	syncB.Close()
+	a.Emit("set", "counter", 6)
+	bus.Deliver()
+
+	if b.Get("counter") != 5 {
+		t.Errorf("closed sync received update: %#v", b.Get("counter"))
+	}
+}
+
+func TestSyncConflict(t *testing.T) {
+	bus := NewMemoryBus()
+
+	var conflicts []Conflict
+	merge := func(s *Store, c Conflict) (interface{}, error) {
+		conflicts = append(conflicts, c)
+		return c.Local.(int) + c.Remote.(int), nil
+	}
+
+	syncA := &Sync{Channel: bus.Channel(), ID: "a", Conflict: merge}
+	a, err := newSyncStore(syncA)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	b, err := newSyncStore(&Sync{Channel: bus.Channel(), ID: "b", Conflict: LocalWins})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	a.Emit("set", "counter", 1)
+	bus.Deliver()
+
+	if len(conflicts) != 0 || b.Get("counter") != 1 {
+		t.Errorf("invalid sync without conflict: %v, %#v", conflicts, b.Get("counter"))
+	}
+
+	a.Emit("set", "counter", 2)
+	b.Emit("set", "counter", 3)
+	bus.Deliver()
+
+	if len(conflicts) != 1 || conflicts[0].Field != "counter" || conflicts[0].Source != "b" {
+		t.Errorf("invalid conflicts: %#v", conflicts)
+		return
+	}
+
+	if a.Get("counter") != 5 || b.Get("counter") != 3 {
+		t.Errorf("invalid conflict resolution: a: %v, b: %v", a.Get("counter"), b.Get("counter"))
+	}
+}
+
+func TestSyncRollback(t *testing.T) {
+	bus := NewMemoryBus()
+
+	syncA := &Sync{Channel: bus.Channel(), ID: "a"}
+	a, err := New(&Store{
+		Data: map[string]interface{}{"counter": 0},
+		Handlers: map[string]Handler{
+			"set": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+				return map[string]interface{}{"counter": values[0]}, nil
+			},
+		},
+		OnCreate: []OnCreateHook{syncA.OnCreate},
+		AfterEmit: []AfterEmitHook{
+			syncA.AfterEmit,
+			func(s *Store, eventName string, updatesMap map[string]interface{}, values []interface{}) error {
+				if updatesMap["counter"] == -1 {
+					return errors.New("invalid counter")
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	syncB := &Sync{Channel: bus.Channel(), ID: "b"}
+	b, err := newSyncStore(syncB)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	if err := a.Emit("set", -1); err == nil {
+		t.Error("expected error")
+	}
+
+	if count := bus.Deliver(); count != 0 {
+		t.Errorf("rolled back event was sent: %d messages", count)
+	}
+
+	a.Emit("set", 2)
+	bus.Deliver()
+
+	if b.Get("counter") != 2 {
+		t.Errorf("counter wasn't synced: %#v", b.Get("counter"))
+	}
+}
+
+func TestSyncDefaultID(t *testing.T) {
+	bus := NewMemoryBus()
+
+	syncA, syncB := &Sync{Channel: bus.Channel()}, &Sync{Channel: bus.Channel()}
+	clock := fixedClock{now: time.Unix(0, 0)}
+
+	var stores []*Store
+	for _, sy := range []*Sync{syncA, syncB} {
+		s, err := New(&Store{
+			Data: map[string]interface{}{"counter": 0},
+			Handlers: map[string]Handler{
+				"set": func(s *Store, values ...interface{}) (map[string]interface{}, error) {
+					return map[string]interface{}{"counter": values[0]}, nil
+				},
+			},
+			Clock:     clock,
+			OnCreate:  []OnCreateHook{sy.OnCreate},
+			AfterEmit: []AfterEmitHook{sy.AfterEmit},
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+
+		stores = append(stores, s)
+	}
+
+	// stores with the same clock must have different ids
+	if syncA.ID == syncB.ID {
+		t.Errorf("stores have the same id: %s", syncA.ID)
+	}
+
+	stores[0].Emit("set", 1)
+	bus.Deliver()
+
+	if stores[1].Get("counter") != 1 {
+		t.Errorf("counter wasn't synced: %#v", stores[1].Get("counter"))
+	}
+}
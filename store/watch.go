@@ -0,0 +1,149 @@
+package store
+
+import (
+	"reflect"
+)
+
+// WatchHandler called when watched value was changed
+type WatchHandler func(old, new interface{})
+
+// WatchOptions options for Store.Watch
+type WatchOptions struct {
+	Deep      bool // compare values by reflect.DeepEqual. By default maps, slices and pointers are compared by address
+	Immediate bool // call handler right after Watch with nil old value
+}
+
+type watcher struct {
+	key     string
+	handler WatchHandler
+	options WatchOptions
+
+	last interface{}
+}
+
+// Watch call handler after store updates if value by key (Data key, nested path or Computed name) was changed.
+// Handler is called while event is processing, so it mustn't call Emit synchronously.
+// Returns function removing watcher
+func (s *Store) Watch(key string, handler WatchHandler, options ...WatchOptions) (unwatch func()) {
+	w := &watcher{key: key, handler: handler}
+	if len(options) != 0 {
+		w.options = options[0]
+	}
+
+	w.last, _ = s.GetWithError(key)
+
+	s.watchMu.Lock()
+	s.watchers = append(s.watchers, w)
+	s.watchMu.Unlock()
+
+	if w.options.Immediate {
+		handler(nil, w.last)
+	}
+
+	return func() {
+		s.watchMu.Lock()
+		defer s.watchMu.Unlock()
+
+		for i, el := range s.watchers {
+			if el == w {
+				s.watchers = append(s.watchers[:i], s.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// runWatchers call watchers depending on changed keys
+func (s *Store) runWatchers(changed []string) {
+	s.watchMu.Lock()
+	watchers := make([]*watcher, len(s.watchers))
+	copy(watchers, s.watchers)
+	s.watchMu.Unlock()
+
+	for _, w := range watchers {
+		if !keysOverlap([]string{w.key}, changed) {
+			continue
+		}
+
+		value, _ := s.GetWithError(w.key)
+
+		s.watchMu.Lock()
+		old := w.last
+
+		var equal bool
+		if w.options.Deep {
+			equal = reflect.DeepEqual(old, value)
+		} else {
+			equal = shallowEqual(old, value)
+		}
+
+		if !equal {
+			w.last = value
+		}
+		s.watchMu.Unlock()
+
+		if !equal {
+			w.handler(old, value)
+		}
+	}
+}
+
+// shallowEqual compare values by ==. Maps, slices and pointers are compared by address,
+// structs, arrays and interfaces are compared by their fields and elements
+func shallowEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return shallowEqualValues(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// shallowEqualValues compare values without Interface(), so unexported fields can be compared too
+func shallowEqualValues(a, b reflect.Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Map, reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	case reflect.Slice:
+		return a.Pointer() == b.Pointer() && a.Len() == b.Len()
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() && b.IsNil()
+		}
+
+		return shallowEqualValues(a.Elem(), b.Elem())
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if !shallowEqualValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !shallowEqualValues(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+	default: // functions
+		return false
+	}
+}
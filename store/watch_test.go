@@ -0,0 +1,114 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestWatch(t *testing.T) {
+	s, err := New(&Store{
+		Data: map[string]interface{}{
+			"counter": 0,
+			"user":    map[string]string{"name": "Artem"},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	type call struct{ old, new interface{} }
+	var counterCalls, nameCalls, userCalls, deepCalls []call
+
+	unwatch := s.Watch("counter", func(old, new interface{}) {
+		counterCalls = append(counterCalls, call{old, new})
+	}, WatchOptions{Immediate: true})
+
+	s.Watch("user.name", func(old, new interface{}) {
+		nameCalls = append(nameCalls, call{old, new})
+	})
+
+	s.Watch("user", func(old, new interface{}) {
+		userCalls = append(userCalls, call{old, new})
+	})
+
+	s.Watch("user", func(old, new interface{}) {
+		deepCalls = append(deepCalls, call{old, new})
+	}, WatchOptions{Deep: true})
+
+	if len(counterCalls) != 1 || counterCalls[0].old != nil || counterCalls[0].new != 0 {
+		t.Errorf("immediate watcher wasn't called: %v", counterCalls)
+	}
+
+	updates := []map[string]interface{}{
+		{"counter": 1},
+		{"counter": 1}, // value isn't changed
+		{"user": map[string]string{"name": "Artem"}},
+		{"user.name": "Elen"},
+	}
+
+	for _, el := range updates {
+		if err := s.UpdateStore(el); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+	}
+
+	if len(counterCalls) != 2 || counterCalls[1].old != 0 || counterCalls[1].new != 1 {
+		t.Errorf("invalid counter watcher calls: %v", counterCalls)
+	}
+
+	if len(nameCalls) != 1 || nameCalls[0].old != "Artem" || nameCalls[0].new != "Elen" {
+		t.Errorf("invalid name watcher calls: %v", nameCalls)
+	}
+
+	if len(userCalls) != 2 {
+		t.Errorf("invalid shallow watcher calls count want: 2, got: %d", len(userCalls))
+	}
+
+	if len(deepCalls) != 1 {
+		t.Errorf("invalid deep watcher calls count want: 1, got: %d", len(deepCalls))
+	}
+
+	unwatch()
+	if err := s.UpdateStore(map[string]interface{}{"counter": 2}); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	if len(counterCalls) != 2 {
+		t.Error("watcher was called after unwatch")
+	}
+}
+
+func TestShallowEqual(t *testing.T) {
+	type item struct {
+		Value interface{}
+		tags  []string
+	}
+
+	slice := []int{1, 2}
+	m := map[string]int{"a": 1}
+
+	data := []struct {
+		a, b  interface{}
+		equal bool
+	}{
+		{a: nil, b: nil, equal: true},
+		{a: 1, b: nil, equal: false},
+		{a: 1, b: 1, equal: true},
+		{a: 1, b: int64(1), equal: false},
+		{a: slice, b: slice, equal: true},
+		{a: slice, b: []int{1, 2}, equal: false},
+		{a: m, b: m, equal: true},
+		{a: item{Value: slice}, b: item{Value: slice}, equal: true},
+		{a: item{Value: slice}, b: item{Value: []int{1, 2}}, equal: false},
+		{a: item{Value: m, tags: []string{"a"}}, b: item{Value: m}, equal: false},
+		{a: [2]interface{}{1, slice}, b: [2]interface{}{1, slice}, equal: true},
+		{a: item{Value: func() {}}, b: item{Value: func() {}}, equal: false},
+	}
+
+	for i, el := range data {
+		if equal := shallowEqual(el.a, el.b); equal != el.equal {
+			t.Errorf("%d: want: %t, got: %t", i, el.equal, equal)
+		}
+	}
+}